@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/storage"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Analyze images without updating the database")
+	flag.Parse()
+
+	// Load configuration
+	// For this command, we only need DATABASE_URL and storage settings, but config.Load requires JWT_SECRET
+	// So we'll set a dummy JWT_SECRET if it's not set
+	if os.Getenv("JWT_SECRET") == "" {
+		os.Setenv("JWT_SECRET", "dummy-secret-for-backfill-command-only-min-32-chars-long")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Connect to database
+	if err := db.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Initialize storage backend
+	if err := storage.Setup(cfg); err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	ctx := context.Background()
+	queries := sqlc.New(db.Pool)
+
+	images, err := queries.ListProjectImagesMissingInfo(ctx)
+	if err != nil {
+		log.Fatalf("Failed to list project images: %v", err)
+	}
+
+	fmt.Printf("Found %d images without dimensions/size/color\n", len(images))
+
+	updated, failed := 0, 0
+	for _, img := range images {
+		info, err := analyzeStoredImage(ctx, img.Url)
+		if err != nil {
+			fmt.Printf("Failed image %d (%s): %v\n", img.ID, img.Url, err)
+			failed++
+			continue
+		}
+
+		if !*dryRun {
+			err = queries.UpdateProjectImageInfo(ctx, sqlc.UpdateProjectImageInfoParams{
+				ID:            img.ID,
+				Width:         pgtype.Int4{Int32: int32(info.Width), Valid: true},
+				Height:        pgtype.Int4{Int32: int32(info.Height), Valid: true},
+				SizeBytes:     pgtype.Int8{Int64: info.SizeBytes, Valid: true},
+				MimeType:      pgtype.Text{String: info.MimeType, Valid: true},
+				DominantColor: pgtype.Text{String: info.DominantColor, Valid: true},
+				BlurHash:      pgtype.Text{String: info.BlurHash, Valid: true},
+			})
+			if err != nil {
+				fmt.Printf("Failed to update image %d (%s): %v\n", img.ID, img.Url, err)
+				failed++
+				continue
+			}
+		}
+
+		fmt.Printf("Image %d: %dx%d, %d bytes, %s, %s\n", img.ID, info.Width, info.Height, info.SizeBytes, info.MimeType, info.DominantColor)
+		updated++
+	}
+
+	fmt.Println("\n=== Backfill Report ===")
+	if *dryRun {
+		fmt.Println("Dry run, nothing written")
+	}
+	fmt.Printf("Updated: %d images\n", updated)
+	fmt.Printf("Failed: %d images\n", failed)
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// analyzeStoredImage loads an image from the storage backend and extracts its properties
+func analyzeStoredImage(ctx context.Context, url string) (*storage.ImageInfo, error) {
+	key, err := storage.KeyFromURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	file, _, err := storage.Store.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return storage.AnalyzeImage(data)
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/slug"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
@@ -212,8 +214,8 @@ func migrateProjects(mysqlDB, postgresDB *sql.DB, dryRun bool) (int, error) {
 	defer tx.Rollback()
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO projects (id, status, name, category, client, "order", highlighted, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO projects (id, status, name, category_id, client, "order", highlighted, slug, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		ON CONFLICT (id) DO NOTHING
 	`)
 	if err != nil {
@@ -222,6 +224,8 @@ func migrateProjects(mysqlDB, postgresDB *sql.DB, dryRun bool) (int, error) {
 	defer stmt.Close()
 
 	count := 0
+	usedSlugs := make(map[string]bool)
+	categoryIDs := make(map[string]int64)
 	for rows.Next() {
 		var id int64
 		var status int
@@ -239,8 +243,31 @@ func migrateProjects(mysqlDB, postgresDB *sql.DB, dryRun bool) (int, error) {
 		createdAt := parseMySQLTime(createdAtStr)
 		updatedAt := parseMySQLTime(updatedAtStr)
 
-		_, err = stmt.Exec(id, status, name, nullableString(category), nullableString(client),
-			order, highlighted, createdAt, updatedAt)
+		// Same slugs as the add_slug_to_projects migration: from the name, ID appended on duplicates
+		projectSlug := slug.Make(name)
+		if projectSlug == "" {
+			projectSlug = fmt.Sprintf("project-%d", id)
+		} else if usedSlugs[projectSlug] {
+			projectSlug = fmt.Sprintf("%s-%d", projectSlug, id)
+		}
+		usedSlugs[projectSlug] = true
+
+		// Same mapping as the add_status_lifecycle_to_projects migration
+		projectStatus := "published"
+		switch status {
+		case 0:
+			projectStatus = "draft"
+		case 2:
+			projectStatus = "archived"
+		}
+
+		categoryID, err := migrateCategory(tx, categoryIDs, category)
+		if err != nil {
+			return count, fmt.Errorf("failed to create category for project %d: %w", id, err)
+		}
+
+		_, err = stmt.Exec(id, projectStatus, name, categoryID, nullableString(client),
+			order, highlighted, projectSlug, createdAt, updatedAt)
 		if err != nil {
 			return count, fmt.Errorf("failed to insert project %d: %w", id, err)
 		}
@@ -590,6 +617,37 @@ func migrateVisitorMessages(mysqlDB, postgresDB *sql.DB, dryRun bool) (int, erro
 	return count, nil
 }
 
+// migrateCategory returns the ID of the category for a free-text MySQL category, creating it
+// on first use. Like the create_categories migration, spellings with the same slug share one
+// category (ids caches slug -> ID)
+func migrateCategory(tx *sql.Tx, ids map[string]int64, category sql.NullString) (*int64, error) {
+	if !category.Valid {
+		return nil, nil
+	}
+	name := strings.TrimSpace(category.String)
+	categorySlug := slug.Make(name)
+	if categorySlug == "" {
+		return nil, nil
+	}
+
+	if id, ok := ids[categorySlug]; ok {
+		return &id, nil
+	}
+
+	var id int64
+	err := tx.QueryRow(`
+		INSERT INTO categories (name, slug, "order", created_at, updated_at)
+		VALUES ($1, $2, (SELECT COALESCE(MAX("order") + 1, 0) FROM categories), NOW(), NOW())
+		ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+		RETURNING id
+	`, name, categorySlug).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	ids[categorySlug] = id
+	return &id, nil
+}
+
 // Helper functions for nullable types
 func nullableString(ns sql.NullString) *string {
 	if ns.Valid {
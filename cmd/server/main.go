@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/http"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/jobs"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/storage"
 )
 
 func main() {
@@ -27,6 +32,30 @@ func main() {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
+	// Initialize storage backend
+	if err := storage.Setup(cfg); err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// Start background storage GC
+	if cfg.StorageGC.Interval > 0 {
+		go storage.RunGCPeriodically(context.Background(), sqlc.New(db.Pool), cfg.StorageGC.Interval, storage.GCOptions{
+			DryRun:      cfg.StorageGC.DryRun,
+			GracePeriod: cfg.StorageGC.GracePeriod,
+		})
+		log.Printf("Storage GC running every %s", cfg.StorageGC.Interval)
+	}
+
+	// Remove resumable uploads that expired without being attached
+	go storage.RunUploadCleanupPeriodically(context.Background(), sqlc.New(db.Pool), time.Hour)
+
+	// Start image processing workers
+	go jobs.RunImageWorkers(context.Background(), cfg)
+	log.Printf("Image processing running with %d workers", cfg.ImageJobs.Workers)
+
+	// Apply scheduled publishing of projects and testimonials
+	go jobs.RunPublishScheduler(context.Background(), cfg.PublishSchedulerInterval)
+
 	// Setup router
 	router := http.SetupRouter(cfg)
 
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/storage"
+)
+
+func main() {
+	var deleteOrphans bool
+	flag.BoolVar(&deleteOrphans, "delete", false, "Delete orphan files (default is a dry run that only reports them)")
+	gracePeriod := flag.Duration("grace-period", 0, "Skip files modified within this period, 0 to include every file (default: STORAGE_GC_GRACE_PERIOD or 24h)")
+	flag.Parse()
+
+	// Load configuration
+	// For this command, we only need DATABASE_URL and storage settings, but config.Load requires JWT_SECRET
+	// So we'll set a dummy JWT_SECRET if it's not set
+	if os.Getenv("JWT_SECRET") == "" {
+		os.Setenv("JWT_SECRET", "dummy-secret-for-storage-gc-command-only-min-32-chars-long")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Connect to database
+	if err := db.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Initialize storage backend
+	if err := storage.Setup(cfg); err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	opts := storage.GCOptions{
+		DryRun:      !deleteOrphans,
+		GracePeriod: cfg.StorageGC.GracePeriod,
+	}
+	// Only an explicit flag overrides the config, so that -grace-period=0 includes every file
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "grace-period" {
+			opts.GracePeriod = *gracePeriod
+		}
+	})
+	if opts.GracePeriod < 0 {
+		log.Fatal("Grace period must not be negative")
+	}
+
+	report, err := storage.CollectGarbage(context.Background(), sqlc.New(db.Pool), opts)
+	if err != nil {
+		log.Fatalf("Storage GC failed: %v", err)
+	}
+
+	// Print report
+	mode := "dry run"
+	if !opts.DryRun {
+		mode = "delete"
+	}
+	fmt.Printf("=== Storage GC Report (%s, grace period %s) ===\n", mode, opts.GracePeriod)
+	fmt.Printf("Scanned: %d files\n", report.ScannedFiles)
+	fmt.Printf("Skipped (recent): %d files\n", report.SkippedRecent)
+	fmt.Printf("Orphans: %d files\n", len(report.OrphanFiles))
+	fmt.Printf("Deleted: %d files\n", len(report.DeletedFiles))
+	fmt.Printf("Dangling image rows: %d\n", len(report.DanglingImages))
+
+	if len(report.OrphanFiles) > 0 {
+		fmt.Println("\nOrphan files:")
+		for _, file := range report.OrphanFiles {
+			fmt.Printf("  - %s (%d bytes, modified %s)\n", storage.PathForKey(file.Key), file.Size, file.ModTime.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	if len(report.DanglingImages) > 0 {
+		fmt.Println("\nDangling image rows (file missing from storage):")
+		for _, img := range report.DanglingImages {
+			fmt.Printf("  - image %d (project %d): %s\n", img.ID, img.ProjectID, img.Url)
+		}
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Println("\nErrors:")
+		for _, err := range report.Errors {
+			fmt.Printf("  - %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
@@ -3,8 +3,13 @@ package config
 import (
 	"fmt"
 	"log"
+	"math"
 	"os"
+	"runtime"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +20,92 @@ type Config struct {
 	JWTSecret   string
 	Port        int
 	StoragePath string
+
+	// StorageDriver selects the storage backend: "local" or "s3"
+	StorageDriver string
+	S3            S3Config
+	StorageGC     StorageGCConfig
+
+	Images    ImageConfig
+	ImageJobs ImageJobsConfig
+	Uploads   UploadConfig
+	Resize    ResizeConfig
+
+	// PublishSchedulerInterval is how often due publish_at/unpublish_at schedules are applied
+	PublishSchedulerInterval time.Duration
+}
+
+// ResizeConfig holds configuration for on-the-fly image resizing
+type ResizeConfig struct {
+	// Sizes is the allow-list of requestable sizes; a zero width or height means "auto"
+	Sizes []ResizeSize
+	// CacheDir is where resized images are cached
+	CacheDir string
+	// CacheSize is the maximum total size of the cache in bytes (least recently used entries are evicted)
+	CacheSize int64
+	// MaxPixels is the largest source image (width x height) that is decoded for resizing
+	MaxPixels int64
+	// MaxConcurrent is the number of resizes that may decode an image at the same time
+	MaxConcurrent int
+}
+
+// ResizeSize is an allowed width x height combination
+type ResizeSize struct {
+	Width  int
+	Height int
+}
+
+// UploadConfig holds limits for multipart file uploads
+type UploadConfig struct {
+	// MaxFileSize is the maximum size of a single uploaded file in bytes
+	MaxFileSize int64
+	// MaxFiles is the maximum number of files in one request
+	MaxFiles int
+	// MaxRequestSize is the maximum size of a whole request body in bytes
+	MaxRequestSize int64
+}
+
+// ImageJobsConfig holds configuration for the background image processing workers
+type ImageJobsConfig struct {
+	// Workers is the number of jobs processed concurrently
+	Workers int
+	// PollInterval is how often idle workers check the queue for new or retried jobs
+	PollInterval time.Duration
+	// MaxAttempts is how many times a job is tried before the image is marked as failed
+	MaxAttempts int
+}
+
+// StorageGCConfig holds configuration for the background storage garbage collector
+type StorageGCConfig struct {
+	// Interval between runs, 0 disables the background job
+	Interval    time.Duration
+	GracePeriod time.Duration
+	DryRun      bool
+}
+
+// ImageConfig holds configuration for the image upload pipeline
+type ImageConfig struct {
+	// VariantWidths are the widths (px) of the downscaled copies generated for every upload
+	VariantWidths []int
+	// JPEGQuality is the encoder quality (1-100) used for JPEG variants
+	JPEGQuality int
+	// WebP enables writing a WebP copy of every upload and variant
+	WebP bool
+	// WebPQuality is the encoder quality (1-100) used for WebP copies
+	WebPQuality int
+	// ExtractMetadata stores EXIF capture date and GPS position before metadata is stripped
+	ExtractMetadata bool
+}
+
+// S3Config holds configuration for the S3-compatible storage driver
+type S3Config struct {
+	Endpoint  string // host[:port], e.g. s3.eu-central-1.amazonaws.com or localhost:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+	PublicURL string // optional base URL (CDN or public bucket) to redirect image requests to
 }
 
 // Load loads configuration from environment variables
@@ -58,5 +149,363 @@ func Load() (*Config, error) {
 		cfg.StoragePath = "./storage"
 	}
 
+	// Storage Driver
+	cfg.StorageDriver = os.Getenv("STORAGE_DRIVER")
+	if cfg.StorageDriver == "" {
+		cfg.StorageDriver = "local"
+	}
+	switch cfg.StorageDriver {
+	case "local":
+	case "s3":
+		if err := loadS3Config(&cfg.S3); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER must be one of: local, s3")
+	}
+
+	// Storage GC
+	if err := loadStorageGCConfig(&cfg.StorageGC); err != nil {
+		return nil, err
+	}
+
+	// Image pipeline
+	if err := loadImageConfig(&cfg.Images); err != nil {
+		return nil, err
+	}
+
+	// Image processing workers
+	if err := loadImageJobsConfig(&cfg.ImageJobs); err != nil {
+		return nil, err
+	}
+
+	// Upload limits
+	if err := loadUploadConfig(&cfg.Uploads); err != nil {
+		return nil, err
+	}
+
+	// On-the-fly resizing
+	if err := loadResizeConfig(&cfg.Resize, cfg.StoragePath); err != nil {
+		return nil, err
+	}
+
+	// Publishing scheduler
+	intervalStr := os.Getenv("PUBLISH_SCHEDULER_INTERVAL")
+	if intervalStr == "" {
+		intervalStr = "1m"
+	}
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("PUBLISH_SCHEDULER_INTERVAL must be a positive duration (e.g. 1m)")
+	}
+	cfg.PublishSchedulerInterval = interval
+
 	return cfg, nil
 }
+
+// loadStorageGCConfig loads the background storage GC settings
+func loadStorageGCConfig(gc *StorageGCConfig) error {
+	if intervalStr := os.Getenv("STORAGE_GC_INTERVAL"); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil {
+			return fmt.Errorf("STORAGE_GC_INTERVAL must be a valid duration (e.g. 6h): %w", err)
+		}
+		gc.Interval = interval
+	}
+
+	graceStr := os.Getenv("STORAGE_GC_GRACE_PERIOD")
+	if graceStr == "" {
+		graceStr = "24h"
+	}
+	grace, err := time.ParseDuration(graceStr)
+	if err != nil {
+		return fmt.Errorf("STORAGE_GC_GRACE_PERIOD must be a valid duration (e.g. 24h): %w", err)
+	}
+	gc.GracePeriod = grace
+
+	dryRunStr := os.Getenv("STORAGE_GC_DRY_RUN")
+	if dryRunStr == "" {
+		dryRunStr = "false"
+	}
+	dryRun, err := strconv.ParseBool(dryRunStr)
+	if err != nil {
+		return fmt.Errorf("STORAGE_GC_DRY_RUN must be a valid boolean: %w", err)
+	}
+	gc.DryRun = dryRun
+
+	return nil
+}
+
+// loadImageConfig loads the image pipeline settings
+func loadImageConfig(images *ImageConfig) error {
+	widthsStr := os.Getenv("IMAGE_VARIANT_WIDTHS")
+	if widthsStr == "" {
+		widthsStr = "320,768,1280,1920"
+	}
+	widths, err := parseIntList(widthsStr)
+	if err != nil {
+		return fmt.Errorf("IMAGE_VARIANT_WIDTHS must be a comma-separated list of positive integers: %w", err)
+	}
+	sort.Ints(widths)
+	images.VariantWidths = widths
+
+	qualityStr := os.Getenv("IMAGE_JPEG_QUALITY")
+	if qualityStr == "" {
+		qualityStr = "85"
+	}
+	quality, err := strconv.Atoi(qualityStr)
+	if err != nil || quality < 1 || quality > 100 {
+		return fmt.Errorf("IMAGE_JPEG_QUALITY must be an integer between 1 and 100")
+	}
+	images.JPEGQuality = quality
+
+	webpStr := os.Getenv("IMAGE_WEBP")
+	if webpStr == "" {
+		webpStr = "true"
+	}
+	webp, err := strconv.ParseBool(webpStr)
+	if err != nil {
+		return fmt.Errorf("IMAGE_WEBP must be a valid boolean: %w", err)
+	}
+	images.WebP = webp
+
+	webpQualityStr := os.Getenv("IMAGE_WEBP_QUALITY")
+	if webpQualityStr == "" {
+		webpQualityStr = "80"
+	}
+	webpQuality, err := strconv.Atoi(webpQualityStr)
+	if err != nil || webpQuality < 1 || webpQuality > 100 {
+		return fmt.Errorf("IMAGE_WEBP_QUALITY must be an integer between 1 and 100")
+	}
+	images.WebPQuality = webpQuality
+
+	extractStr := os.Getenv("IMAGE_EXTRACT_METADATA")
+	if extractStr == "" {
+		extractStr = "false"
+	}
+	extract, err := strconv.ParseBool(extractStr)
+	if err != nil {
+		return fmt.Errorf("IMAGE_EXTRACT_METADATA must be a valid boolean: %w", err)
+	}
+	images.ExtractMetadata = extract
+
+	return nil
+}
+
+// loadImageJobsConfig loads the image processing worker settings
+func loadImageJobsConfig(jobs *ImageJobsConfig) error {
+	workersStr := os.Getenv("IMAGE_WORKERS")
+	if workersStr == "" {
+		workersStr = "2"
+	}
+	workers, err := strconv.Atoi(workersStr)
+	if err != nil || workers < 1 {
+		return fmt.Errorf("IMAGE_WORKERS must be a positive integer")
+	}
+	jobs.Workers = workers
+
+	pollStr := os.Getenv("IMAGE_WORKER_POLL_INTERVAL")
+	if pollStr == "" {
+		pollStr = "5s"
+	}
+	poll, err := time.ParseDuration(pollStr)
+	if err != nil || poll <= 0 {
+		return fmt.Errorf("IMAGE_WORKER_POLL_INTERVAL must be a positive duration (e.g. 5s)")
+	}
+	jobs.PollInterval = poll
+
+	attemptsStr := os.Getenv("IMAGE_JOB_MAX_ATTEMPTS")
+	if attemptsStr == "" {
+		attemptsStr = "5"
+	}
+	attempts, err := strconv.Atoi(attemptsStr)
+	if err != nil || attempts < 1 {
+		return fmt.Errorf("IMAGE_JOB_MAX_ATTEMPTS must be a positive integer")
+	}
+	jobs.MaxAttempts = attempts
+
+	return nil
+}
+
+// loadUploadConfig loads the upload size limits
+func loadUploadConfig(uploads *UploadConfig) error {
+	maxFileSizeStr := os.Getenv("UPLOAD_MAX_FILE_SIZE")
+	if maxFileSizeStr == "" {
+		maxFileSizeStr = "20MB"
+	}
+	maxFileSize, err := parseByteSize(maxFileSizeStr)
+	if err != nil {
+		return fmt.Errorf("UPLOAD_MAX_FILE_SIZE must be a size in bytes (e.g. 20MB): %w", err)
+	}
+	uploads.MaxFileSize = maxFileSize
+
+	maxFilesStr := os.Getenv("UPLOAD_MAX_FILES")
+	if maxFilesStr == "" {
+		maxFilesStr = "50"
+	}
+	maxFiles, err := strconv.Atoi(maxFilesStr)
+	if err != nil || maxFiles < 1 {
+		return fmt.Errorf("UPLOAD_MAX_FILES must be a positive integer")
+	}
+	uploads.MaxFiles = maxFiles
+
+	maxRequestSizeStr := os.Getenv("UPLOAD_MAX_REQUEST_SIZE")
+	if maxRequestSizeStr == "" {
+		maxRequestSizeStr = "500MB"
+	}
+	maxRequestSize, err := parseByteSize(maxRequestSizeStr)
+	if err != nil {
+		return fmt.Errorf("UPLOAD_MAX_REQUEST_SIZE must be a size in bytes (e.g. 500MB): %w", err)
+	}
+	uploads.MaxRequestSize = maxRequestSize
+
+	return nil
+}
+
+// loadResizeConfig loads the on-the-fly resize settings
+func loadResizeConfig(resize *ResizeConfig, storagePath string) error {
+	sizesStr := os.Getenv("IMAGE_RESIZE_SIZES")
+	if sizesStr == "" {
+		sizesStr = "150x150,300x200,320x0,640x0,768x0,1280x0,1920x0"
+	}
+	for _, entry := range strings.Split(sizesStr, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		w, h, ok := strings.Cut(entry, "x")
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if !ok || errW != nil || errH != nil || width < 0 || height < 0 || (width == 0 && height == 0) {
+			return fmt.Errorf("IMAGE_RESIZE_SIZES must be a comma-separated list of WIDTHxHEIGHT entries (0 = auto), got %q", entry)
+		}
+		resize.Sizes = append(resize.Sizes, ResizeSize{Width: width, Height: height})
+	}
+
+	resize.CacheDir = os.Getenv("IMAGE_RESIZE_CACHE_DIR")
+	if resize.CacheDir == "" {
+		resize.CacheDir = storagePath + "/cache"
+	}
+
+	cacheSizeStr := os.Getenv("IMAGE_RESIZE_CACHE_SIZE")
+	if cacheSizeStr == "" {
+		cacheSizeStr = "1GB"
+	}
+	cacheSize, err := parseByteSize(cacheSizeStr)
+	if err != nil {
+		return fmt.Errorf("IMAGE_RESIZE_CACHE_SIZE must be a size in bytes (e.g. 1GB): %w", err)
+	}
+	resize.CacheSize = cacheSize
+
+	maxPixelsStr := os.Getenv("IMAGE_RESIZE_MAX_PIXELS")
+	if maxPixelsStr == "" {
+		maxPixelsStr = "50000000"
+	}
+	maxPixels, err := strconv.ParseInt(maxPixelsStr, 10, 64)
+	if err != nil || maxPixels < 1 {
+		return fmt.Errorf("IMAGE_RESIZE_MAX_PIXELS must be a positive integer")
+	}
+	resize.MaxPixels = maxPixels
+
+	resize.MaxConcurrent = runtime.NumCPU()
+	if concurrentStr := os.Getenv("IMAGE_RESIZE_CONCURRENCY"); concurrentStr != "" {
+		concurrent, err := strconv.Atoi(concurrentStr)
+		if err != nil || concurrent < 1 {
+			return fmt.Errorf("IMAGE_RESIZE_CONCURRENCY must be a positive integer")
+		}
+		resize.MaxConcurrent = concurrent
+	}
+
+	return nil
+}
+
+// parseByteSize parses a positive byte count with an optional KB/MB/GB suffix (powers of 1024)
+func parseByteSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("%d is not a positive size", n)
+	}
+	if n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("%s is too large", s)
+	}
+	return n * multiplier, nil
+}
+
+// parseIntList parses a comma-separated list of positive integers, ignoring empty entries
+func parseIntList(s string) ([]int, error) {
+	var result []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		if n <= 0 {
+			return nil, fmt.Errorf("%d is not a positive integer", n)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// loadS3Config loads the S3 driver settings, which are only required when STORAGE_DRIVER=s3
+func loadS3Config(s3 *S3Config) error {
+	s3.Endpoint = os.Getenv("S3_ENDPOINT")
+	if s3.Endpoint == "" {
+		return fmt.Errorf("S3_ENDPOINT is required when STORAGE_DRIVER=s3")
+	}
+
+	s3.Bucket = os.Getenv("S3_BUCKET")
+	if s3.Bucket == "" {
+		return fmt.Errorf("S3_BUCKET is required when STORAGE_DRIVER=s3")
+	}
+
+	s3.AccessKey = os.Getenv("S3_ACCESS_KEY")
+	s3.SecretKey = os.Getenv("S3_SECRET_KEY")
+	if s3.AccessKey == "" || s3.SecretKey == "" {
+		return fmt.Errorf("S3_ACCESS_KEY and S3_SECRET_KEY are required when STORAGE_DRIVER=s3")
+	}
+
+	s3.Region = os.Getenv("S3_REGION")
+	if s3.Region == "" {
+		s3.Region = "us-east-1"
+	}
+
+	useSSLStr := os.Getenv("S3_USE_SSL")
+	if useSSLStr == "" {
+		useSSLStr = "true"
+	}
+	useSSL, err := strconv.ParseBool(useSSLStr)
+	if err != nil {
+		return fmt.Errorf("S3_USE_SSL must be a valid boolean: %w", err)
+	}
+	s3.UseSSL = useSSL
+
+	s3.PublicURL = os.Getenv("S3_PUBLIC_URL")
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package config
+
+import "testing"
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"1024", 1024, false},
+		{"100B", 100, false},
+		{"1KB", 1 << 10, false},
+		{"20MB", 20 << 20, false},
+		{"1GB", 1 << 30, false},
+		{" 5 mb ", 5 << 20, false},
+		{"2gb", 2 << 30, false},
+		{"", 0, true},
+		{"MB", 0, true},
+		{"0", 0, true},
+		{"-5MB", 0, true},
+		{"1.5GB", 0, true},
+		{"10TB", 0, true},
+		{"abc", 0, true},
+		{"9223372036854775807", 9223372036854775807, false},
+		{"9999999999999GB", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseByteSize(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseByteSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseByteSize(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
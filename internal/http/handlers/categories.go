@@ -0,0 +1,485 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/models"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/slug"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CategoryRequest is the body of POST /api/categories and PUT /api/categories/:id
+type CategoryRequest struct {
+	Name  string `json:"name" binding:"required,max=255"`
+	Slug  string `json:"slug"`
+	Order *int   `json:"order"`
+}
+
+// ReorderCategoriesRequest is the body of PUT /api/categories/order
+type ReorderCategoriesRequest struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+// GetCategories returns all categories in display order, with the number of projects in each
+func GetCategories(c *gin.Context) {
+	respondWithCategories(c, sqlc.New(db.Pool), false)
+}
+
+// GetPublicCategories returns all categories in display order, with the number of published projects in each
+func GetPublicCategories(c *gin.Context) {
+	respondWithCategories(c, sqlc.New(db.Pool), true)
+}
+
+// GetCategory returns a single category
+func GetCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	category, err := queries.GetCategoryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusNotFound, "Category not found")
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, mapSQLCCategoryToModel(category))
+}
+
+// CreateCategory creates a category. Without an order it is added at the end
+func CreateCategory(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		ErrorResponse(c, http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	categorySlug, ok := categorySlugFromRequest(c, req.Slug, name)
+	if !ok {
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	if !ensureCategorySlugFree(c, queries, categorySlug, 0) {
+		return
+	}
+
+	var order int32
+	if req.Order != nil {
+		order = int32(*req.Order)
+	} else {
+		next, err := queries.NextCategoryOrder(ctx)
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+		order = next
+	}
+
+	category, err := queries.CreateCategory(ctx, sqlc.CreateCategoryParams{
+		Name:  name,
+		Slug:  categorySlug,
+		Order: order,
+	})
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to create category")
+		return
+	}
+
+	SuccessResponse(c, http.StatusCreated, mapSQLCCategoryToModel(category))
+}
+
+// UpdateCategory renames a category or changes its slug or order.
+// The slug is regenerated on rename unless one is given; an omitted order is kept
+func UpdateCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		ErrorResponse(c, http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	category, err := queries.GetCategoryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusNotFound, "Category not found")
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	categorySlug := category.Slug
+	if req.Slug != "" || name != category.Name {
+		categorySlug, ok = categorySlugFromRequest(c, req.Slug, name)
+		if !ok {
+			return
+		}
+		if !ensureCategorySlugFree(c, queries, categorySlug, id) {
+			return
+		}
+	}
+
+	order := category.Order
+	if req.Order != nil {
+		order = int32(*req.Order)
+	}
+
+	updated, err := queries.UpdateCategory(ctx, sqlc.UpdateCategoryParams{
+		ID:    id,
+		Name:  name,
+		Slug:  categorySlug,
+		Order: order,
+	})
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update category")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, mapSQLCCategoryToModel(updated))
+}
+
+// ReorderCategories sets the display order of all categories at once
+func ReorderCategories(c *gin.Context) {
+	var req ReorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	// Start transaction
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := queries.WithTx(tx)
+
+	categoryIDs, err := qtx.ListCategoryIDsByOrder(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	// The list must contain every category exactly once
+	exists := make(map[int64]bool, len(categoryIDs))
+	for _, categoryID := range categoryIDs {
+		exists[categoryID] = true
+	}
+	seen := make(map[int64]bool, len(req.IDs))
+	for _, categoryID := range req.IDs {
+		if !exists[categoryID] || seen[categoryID] {
+			ErrorResponse(c, http.StatusBadRequest, "Category IDs must list every category exactly once")
+			return
+		}
+		seen[categoryID] = true
+	}
+	if len(req.IDs) != len(categoryIDs) {
+		ErrorResponse(c, http.StatusBadRequest, "Category IDs must list every category exactly once")
+		return
+	}
+
+	if err := qtx.SetCategoryOrder(ctx, req.IDs); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update category order")
+		return
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+		return
+	}
+
+	respondWithCategories(c, queries, false)
+}
+
+// DeleteCategory deletes a category. Its projects become uncategorized, or are
+// moved to another category with ?move_to=<id> (e.g. to merge two categories)
+func DeleteCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	var moveTo int64
+	if moveToStr := c.Query("move_to"); moveToStr != "" {
+		var err error
+		moveTo, err = strconv.ParseInt(moveToStr, 10, 64)
+		if err != nil || moveTo == id {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid move_to", "move_to must be the ID of another category")
+			return
+		}
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	// Check if category exists
+	_, err := queries.GetCategoryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusNotFound, "Category not found")
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	if moveTo != 0 {
+		if _, err := queries.GetCategoryByID(ctx, moveTo); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				ErrorResponse(c, http.StatusBadRequest, "Invalid move_to", "move_to must be the ID of another category")
+				return
+			}
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+	}
+
+	// Start transaction
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := queries.WithTx(tx)
+
+	if moveTo != 0 {
+		err = qtx.MoveProjectsToCategory(ctx, sqlc.MoveProjectsToCategoryParams{
+			CategoryID:   pgtype.Int8{Int64: id, Valid: true},
+			CategoryID_2: pgtype.Int8{Int64: moveTo, Valid: true},
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to move projects")
+			return
+		}
+	}
+
+	// Remaining projects are uncategorized by the foreign key (ON DELETE SET NULL)
+	if err := qtx.DeleteCategory(ctx, id); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to delete category")
+		return
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondWithCategories writes all categories with their project counts.
+// With publishedOnly, only projects visible on the public site are counted
+func respondWithCategories(c *gin.Context, queries *sqlc.Queries, publishedOnly bool) {
+	rows, err := queries.ListCategoriesWithProjectCounts(c.Request.Context(), publishedOnly)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	categoryModels := make([]models.Category, len(rows))
+	for i, row := range rows {
+		count := row.ProjectCount
+		categoryModels[i] = models.Category{
+			ID:           row.ID,
+			Name:         row.Name,
+			Slug:         row.Slug,
+			Order:        int(row.Order),
+			ProjectCount: &count,
+			CreatedAt:    row.CreatedAt.Time,
+			UpdatedAt:    row.UpdatedAt.Time,
+		}
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"data": categoryModels})
+}
+
+// categorySlugFromRequest normalizes the requested slug, or derives one from the name.
+// It responds with 400 and returns false when nothing usable is left
+func categorySlugFromRequest(c *gin.Context, requested, name string) (string, bool) {
+	source := requested
+	if source == "" {
+		source = name
+	}
+
+	s := slug.Make(source)
+	if s == "" {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid slug", "slug must contain letters or digits")
+		return "", false
+	}
+	return s, true
+}
+
+// ensureCategorySlugFree writes a 409/500 response and returns false if another category owns the slug
+func ensureCategorySlugFree(c *gin.Context, queries *sqlc.Queries, categorySlug string, id int64) bool {
+	taken, err := queries.CategorySlugTaken(c.Request.Context(), sqlc.CategorySlugTakenParams{
+		Slug: categorySlug,
+		ID:   id,
+	})
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return false
+	}
+	if taken {
+		ErrorResponse(c, http.StatusConflict, "Category already exists", "slug "+categorySlug+" is used by another category")
+		return false
+	}
+	return true
+}
+
+// legacyCategoryAliases maps slugs of English category names to the Serbian category they were
+// merged into by migration 000016 (same table as its category_aliases)
+var legacyCategoryAliases = map[string]string{
+	"residential":    "stambeni",
+	"commercial":     "poslovni",
+	"business":       "poslovni",
+	"industrial":     "industrijski",
+	"infrastructure": "infrastruktura",
+	"renovation":     "renoviranje",
+	"reconstruction": "rekonstrukcija",
+}
+
+// getFormCategory reads the project's category from the "category_id" form field, or from
+// the "category" field (a category name or slug, for older clients). It responds with 400
+// and returns false when the category does not exist; no field means no category.
+func getFormCategory(c *gin.Context, queries *sqlc.Queries, form *uploadForm) (pgtype.Int8, bool) {
+	ctx := c.Request.Context()
+
+	var category sqlc.Category
+	var err error
+	if idStr := getFormValue(form, "category_id"); idStr != "" {
+		id, parseErr := strconv.ParseInt(idStr, 10, 64)
+		if parseErr != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid category_id")
+			return pgtype.Int8{}, false
+		}
+		category, err = queries.GetCategoryByID(ctx, id)
+	} else if name := strings.TrimSpace(getFormValue(form, "category")); name != "" {
+		categorySlug := slug.Make(name)
+		category, err = queries.GetCategoryBySlug(ctx, categorySlug)
+		// English names were folded into the Serbian category by the categories migration
+		if canonical, ok := legacyCategoryAliases[categorySlug]; ok && errors.Is(err, pgx.ErrNoRows) {
+			category, err = queries.GetCategoryBySlug(ctx, canonical)
+		}
+	} else {
+		return pgtype.Int8{}, true
+	}
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusBadRequest, "Unknown category", "create the category with POST /api/categories first")
+			return pgtype.Int8{}, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return pgtype.Int8{}, false
+	}
+	return pgtype.Int8{Int64: category.ID, Valid: true}, true
+}
+
+// attachProjectCategories fills in the category name and slug of the given projects
+func attachProjectCategories(ctx context.Context, queries *sqlc.Queries, projects []models.Project) error {
+	var ids []int64
+	for _, p := range projects {
+		if p.CategoryID != nil {
+			ids = append(ids, *p.CategoryID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	categories, err := queries.ListCategoriesByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int64]sqlc.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+	for i := range projects {
+		if projects[i].CategoryID == nil {
+			continue
+		}
+		if category, ok := byID[*projects[i].CategoryID]; ok {
+			projects[i].Category = &category.Name
+			projects[i].CategorySlug = &category.Slug
+		}
+	}
+	return nil
+}
+
+// attachProjectCategory fills in the category name and slug of a single project
+func attachProjectCategory(ctx context.Context, queries *sqlc.Queries, p *models.Project) error {
+	if p.CategoryID == nil {
+		return nil
+	}
+
+	category, err := queries.GetCategoryByID(ctx, *p.CategoryID)
+	if err != nil {
+		// Deleted in the meantime: the project is uncategorized now
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	p.Category = &category.Name
+	p.CategorySlug = &category.Slug
+	return nil
+}
+
+func mapSQLCCategoryToModel(category sqlc.Category) models.Category {
+	return models.Category{
+		ID:        category.ID,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		Order:     int(category.Order),
+		CreatedAt: category.CreatedAt.Time,
+		UpdatedAt: category.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxPageLimit caps ?limit= (cursor mode) and ?per_page= (page mode) of the admin lists
+const maxPageLimit = 100
+
+// pageCursor is the decoded form of the opaque ?cursor= parameter: the sort key and ID of
+// the row a page starts after, or ends before when Before is set (prev_cursor)
+type pageCursor struct {
+	Sort   string     `json:"s"` // sort the cursor was issued for, e.g. "name:asc"
+	ID     int64      `json:"id"`
+	Int    int64      `json:"i,omitempty"`
+	Text   string     `json:"x,omitempty"`
+	Time   *time.Time `json:"t,omitempty"`
+	Before bool       `json:"b,omitempty"`
+}
+
+func (p pageCursor) encode() string {
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// cursorRequest holds the ?cursor=&limit= parameters of a list in cursor mode
+type cursorRequest struct {
+	Sort   string
+	Limit  int
+	Cursor *pageCursor // nil for the first page
+}
+
+// before reports whether the page ends before the cursor (a prev_cursor was followed).
+// Such pages are fetched in reverse order
+func (r cursorRequest) before() bool {
+	return r.Cursor != nil && r.Cursor.Before
+}
+
+// afterID is the cursor row's ID, 0 for the first page
+func (r cursorRequest) afterID() int64 {
+	if r.Cursor == nil {
+		return 0
+	}
+	return r.Cursor.ID
+}
+
+// afterInt is the cursor row's integer sort key
+func (r cursorRequest) afterInt() int32 {
+	if r.Cursor == nil {
+		return 0
+	}
+	return int32(r.Cursor.Int)
+}
+
+// afterText is the cursor row's text sort key
+func (r cursorRequest) afterText() string {
+	if r.Cursor == nil {
+		return ""
+	}
+	return r.Cursor.Text
+}
+
+// afterTime is the cursor row's timestamp sort key
+func (r cursorRequest) afterTime() pgtype.Timestamp {
+	if r.Cursor == nil || r.Cursor.Time == nil {
+		return pgtype.Timestamp{Valid: true}
+	}
+	return pgtype.Timestamp{Time: *r.Cursor.Time, Valid: true}
+}
+
+// fetchDescending tells the keyset query which way to read: the list's own direction,
+// flipped for pages before the cursor
+func (r cursorRequest) fetchDescending(desc bool) bool {
+	return desc != r.before()
+}
+
+// parseCursorRequest reads ?cursor= and ?limit=. It returns cursorMode=false when neither is
+// given, so the list keeps its ?page= mode. It responds with 400 and returns ok=false when the
+// cursor is malformed or was issued for a different sort (sort identifies the list's order).
+func parseCursorRequest(c *gin.Context, sort string, defaultLimit, maxLimit int) (req cursorRequest, cursorMode, ok bool) {
+	cursorStr, hasCursor := c.GetQuery("cursor")
+	limitStr, hasLimit := c.GetQuery("limit")
+	if !hasCursor && !hasLimit {
+		return req, false, true
+	}
+
+	req = cursorRequest{Sort: sort, Limit: defaultLimit}
+	if limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid limit", "limit must be a positive integer")
+			return req, true, false
+		}
+		req.Limit = min(limit, maxLimit)
+	}
+
+	if cursorStr != "" {
+		var cursor pageCursor
+		data, err := base64.RawURLEncoding.DecodeString(cursorStr)
+		if err == nil {
+			err = json.Unmarshal(data, &cursor)
+		}
+		if err != nil || cursor.ID < 1 || cursor.Sort != sort {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid cursor", "cursor must come from next_cursor or prev_cursor of the same list and sort")
+			return req, true, false
+		}
+		req.Cursor = &cursor
+	}
+	return req, true, true
+}
+
+// paginateCursor turns the rows of a keyset query, fetched with limit+1 in fetch order,
+// into a page in display order with its next and prev cursors. key returns the sort key of a row.
+func paginateCursor[T any](req cursorRequest, rows []T, key func(T) pageCursor) ([]T, *string, *string) {
+	hasMore := len(rows) > req.Limit
+	if hasMore {
+		rows = rows[:req.Limit]
+	}
+	if req.before() {
+		slices.Reverse(rows)
+	}
+	if len(rows) == 0 {
+		return rows, nil, nil
+	}
+
+	cursorAt := func(row T, before bool) *string {
+		cursor := key(row)
+		cursor.Sort = req.Sort
+		cursor.Before = before
+		encoded := cursor.encode()
+		return &encoded
+	}
+
+	var next, prev *string
+	if req.before() {
+		// Coming back from a later page: there is always a next page, and more before when the query had spare rows
+		next = cursorAt(rows[len(rows)-1], false)
+		if hasMore {
+			prev = cursorAt(rows[0], true)
+		}
+	} else {
+		if hasMore {
+			next = cursorAt(rows[len(rows)-1], false)
+		}
+		if req.Cursor != nil {
+			prev = cursorAt(rows[0], true)
+		}
+	}
+	return rows, next, prev
+}
+
+// projectCursor returns the key function of projects ordered by sortBy (then ID)
+func projectCursor(sortBy string) func(sqlc.Project) pageCursor {
+	return func(p sqlc.Project) pageCursor {
+		switch sortBy {
+		case "name":
+			return pageCursor{ID: p.ID, Text: p.Name}
+		case "created_at":
+			return timeCursor(p.ID, p.CreatedAt)
+		case "completion_year":
+			// Projects without a year sort as 0, like in ListPublicProjectsKeyset
+			return pageCursor{ID: p.ID, Int: int64(p.CompletionYear.Int32)}
+		default:
+			return pageCursor{ID: p.ID, Int: int64(p.Order)}
+		}
+	}
+}
+
+// timeCursor is the key of rows ordered by a timestamp (then ID)
+func timeCursor(id int64, t pgtype.Timestamp) pageCursor {
+	ts := t.Time
+	return pageCursor{ID: id, Time: &ts}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testContext returns a gin context for a GET request with the given query string
+func testContext(query string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	return c, w
+}
+
+func TestParseCursorRequest(t *testing.T) {
+	valid := pageCursor{Sort: "name:asc", ID: 7, Text: "Bridge"}.encode()
+
+	tests := []struct {
+		name           string
+		query          string
+		wantCursorMode bool
+		wantOK         bool
+		wantLimit      int
+		wantCursor     *pageCursor
+	}{
+		{name: "page mode", query: "page=2", wantCursorMode: false, wantOK: true},
+		{name: "limit only", query: "limit=5", wantCursorMode: true, wantOK: true, wantLimit: 5},
+		{name: "empty cursor is the first page", query: "cursor=", wantCursorMode: true, wantOK: true, wantLimit: 20},
+		{name: "limit capped", query: "limit=1000", wantCursorMode: true, wantOK: true, wantLimit: 100},
+		{name: "zero limit", query: "limit=0", wantCursorMode: true},
+		{name: "non numeric limit", query: "limit=ten", wantCursorMode: true},
+		{
+			name:           "valid cursor",
+			query:          "cursor=" + valid,
+			wantCursorMode: true,
+			wantOK:         true,
+			wantLimit:      20,
+			wantCursor:     &pageCursor{Sort: "name:asc", ID: 7, Text: "Bridge"},
+		},
+		{name: "cursor for another sort", query: "cursor=" + pageCursor{Sort: "name:desc", ID: 7}.encode(), wantCursorMode: true},
+		{name: "cursor without id", query: "cursor=" + pageCursor{Sort: "name:asc"}.encode(), wantCursorMode: true},
+		{name: "cursor not base64", query: "cursor=!!!", wantCursorMode: true},
+		{name: "cursor not json", query: "cursor=bm90LWpzb24", wantCursorMode: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := testContext(tt.query)
+			req, cursorMode, ok := parseCursorRequest(c, "name:asc", 20, maxPageLimit)
+			if cursorMode != tt.wantCursorMode || ok != tt.wantOK {
+				t.Fatalf("parseCursorRequest() cursorMode = %v, ok = %v, want %v, %v", cursorMode, ok, tt.wantCursorMode, tt.wantOK)
+			}
+			if !ok {
+				if w.Code != http.StatusBadRequest {
+					t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+				}
+				return
+			}
+			if !cursorMode {
+				return
+			}
+			if req.Limit != tt.wantLimit {
+				t.Errorf("Limit = %d, want %d", req.Limit, tt.wantLimit)
+			}
+			switch {
+			case (req.Cursor == nil) != (tt.wantCursor == nil):
+				t.Errorf("Cursor = %+v, want %+v", req.Cursor, tt.wantCursor)
+			case req.Cursor != nil && *req.Cursor != *tt.wantCursor:
+				t.Errorf("Cursor = %+v, want %+v", *req.Cursor, *tt.wantCursor)
+			}
+		})
+	}
+}
+
+// keysetFetch mimics a keyset query over ids 1..total ordered by id ascending: it returns
+// up to limit+1 rows after the cursor, or before it in reverse order for prev cursors
+func keysetFetch(total int, req cursorRequest) []int64 {
+	var rows []int64
+	if req.before() {
+		for id := req.afterID() - 1; id >= 1 && len(rows) <= req.Limit; id-- {
+			rows = append(rows, id)
+		}
+		return rows
+	}
+	for id := req.afterID() + 1; id <= int64(total) && len(rows) <= req.Limit; id++ {
+		rows = append(rows, id)
+	}
+	return rows
+}
+
+// followCursor parses an encoded cursor the way the handlers do
+func followCursor(t *testing.T, sort string, limit int, cursor string) cursorRequest {
+	t.Helper()
+	c, w := testContext("limit=" + strconv.Itoa(limit) + "&cursor=" + cursor)
+	req, _, ok := parseCursorRequest(c, sort, limit, maxPageLimit)
+	if !ok {
+		t.Fatalf("cursor %q rejected with %d: %s", cursor, w.Code, w.Body.String())
+	}
+	return req
+}
+
+func TestPaginateCursorRoundTrip(t *testing.T) {
+	const sort, limit, total = "order:asc", 3, 10
+	key := func(id int64) pageCursor { return pageCursor{ID: id, Int: id} }
+	wantPages := [][]int64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {10}}
+
+	// Forward through next_cursor
+	req := cursorRequest{Sort: sort, Limit: limit}
+	var prev *string
+	for i, want := range wantPages {
+		var page []int64
+		var next *string
+		page, next, prev = paginateCursor(req, keysetFetch(total, req), key)
+		if !slices.Equal(page, want) {
+			t.Fatalf("forward page %d = %v, want %v", i, page, want)
+		}
+		if (prev == nil) != (i == 0) {
+			t.Errorf("forward page %d prev_cursor = %v, want set %v", i, prev, i > 0)
+		}
+		if last := i == len(wantPages)-1; (next == nil) != last {
+			t.Fatalf("forward page %d next_cursor = %v, want set %v", i, next, !last)
+		}
+		if next != nil {
+			req = followCursor(t, sort, limit, *next)
+		}
+	}
+
+	// Back through prev_cursor from the last page
+	for i := len(wantPages) - 2; i >= 0; i-- {
+		req = followCursor(t, sort, limit, *prev)
+		var page []int64
+		var next *string
+		page, next, prev = paginateCursor(req, keysetFetch(total, req), key)
+		if !slices.Equal(page, wantPages[i]) {
+			t.Fatalf("backward page %d = %v, want %v", i, page, wantPages[i])
+		}
+		if next == nil {
+			t.Errorf("backward page %d has no next_cursor", i)
+		}
+		if (prev == nil) != (i == 0) {
+			t.Fatalf("backward page %d prev_cursor = %v, want set %v", i, prev, i > 0)
+		}
+
+		// next_cursor of a page reached backwards leads to the same following page
+		if next != nil {
+			forward := followCursor(t, sort, limit, *next)
+			following, _, _ := paginateCursor(forward, keysetFetch(total, forward), key)
+			if !slices.Equal(following, wantPages[i+1]) {
+				t.Errorf("next of backward page %d = %v, want %v", i, following, wantPages[i+1])
+			}
+		}
+	}
+}
+
+func TestPaginateCursorMismatchedSort(t *testing.T) {
+	key := func(id int64) pageCursor { return pageCursor{ID: id, Int: id} }
+	req := cursorRequest{Sort: "order:asc", Limit: 2}
+	_, next, _ := paginateCursor(req, []int64{1, 2, 3}, key)
+	if next == nil {
+		t.Fatal("paginateCursor() returned no next_cursor")
+	}
+
+	c, w := testContext("cursor=" + *next)
+	if _, _, ok := parseCursorRequest(c, "order:desc", 2, maxPageLimit); ok {
+		t.Fatal("parseCursorRequest() accepted a cursor issued for another sort")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPaginateCursorEmpty(t *testing.T) {
+	key := func(id int64) pageCursor { return pageCursor{ID: id} }
+	for _, req := range []cursorRequest{
+		{Sort: "order:asc", Limit: 3},
+		{Sort: "order:asc", Limit: 3, Cursor: &pageCursor{ID: 5, Before: true}},
+	} {
+		page, next, prev := paginateCursor(req, nil, key)
+		if len(page) != 0 || next != nil || prev != nil {
+			t.Errorf("paginateCursor(%+v, nil) = %v, %v, %v, want empty page without cursors", req, page, next, prev)
+		}
+	}
+}
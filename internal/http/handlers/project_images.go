@@ -0,0 +1,604 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/jobs"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// UpdateProjectImageRequest is the body of PUT /api/projects/:id/images/:imageId.
+// Omitted fields keep their current value; an empty alt_text or caption clears it.
+type UpdateProjectImageRequest struct {
+	Name    *string `json:"name" binding:"omitempty,min=1"`
+	AltText *string `json:"alt_text" binding:"omitempty,max=255"`
+	Caption *string `json:"caption" binding:"omitempty,max=2000"`
+}
+
+// ReorderProjectImagesRequest is the body of PUT /api/projects/:id/images/order
+type ReorderProjectImagesRequest struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+// AddProjectImages uploads one or more images and appends them to a project
+// (multipart: files[] or files[0], files[1], ...)
+func AddProjectImages(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIDParam(c, "id", "Invalid project ID")
+		if !ok {
+			return
+		}
+
+		// Stream the multipart form to temporary files
+		form, err := parseUploadForm(c, cfg.Uploads)
+		if err != nil {
+			respondUploadError(c, err)
+			return
+		}
+		defer form.RemoveAll()
+
+		files := getUploadedFiles(form)
+		if len(files) == 0 {
+			ErrorResponse(c, http.StatusBadRequest, "At least one file is required")
+			return
+		}
+
+		queries := sqlc.New(db.Pool)
+		ctx := c.Request.Context()
+
+		if !ensureProjectExists(c, queries, id) {
+			return
+		}
+
+		stored, err := storeUploadedImages(ctx, cfg, files)
+		if err != nil {
+			respondUploadError(c, err)
+			return
+		}
+
+		// Start transaction
+		tx, err := db.Pool.Begin(ctx)
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		qtx := queries.WithTx(tx)
+
+		// New images go after the existing ones
+		nextOrder, err := nextImageOrder(ctx, qtx, id)
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		for i, img := range stored {
+			if _, err := recordUploadedImage(ctx, qtx, cfg, id, img, nextOrder+i, false); err != nil {
+				respondUploadError(c, err)
+				return
+			}
+		}
+
+		// Commit transaction
+		if err := tx.Commit(ctx); err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+			return
+		}
+
+		// Start processing the uploaded images
+		jobs.Notify()
+
+		respondWithProject(c, queries, id, http.StatusCreated)
+	}
+}
+
+// UpdateProjectImageDetails updates an image's metadata
+func UpdateProjectImageDetails(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+	imageID, ok := parseIDParam(c, "imageId", "Invalid project image ID")
+	if !ok {
+		return
+	}
+
+	var req UpdateProjectImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	img, ok := getProjectImageForProject(c, queries, projectID, imageID)
+	if !ok {
+		return
+	}
+
+	params := sqlc.UpdateProjectImageDetailsParams{
+		ID:      img.ID,
+		Name:    img.Name,
+		AltText: img.AltText,
+		Caption: img.Caption,
+	}
+	if req.Name != nil {
+		params.Name = strings.TrimSpace(*req.Name)
+		if params.Name == "" {
+			ErrorResponse(c, http.StatusBadRequest, "Name cannot be empty")
+			return
+		}
+	}
+	if req.AltText != nil {
+		params.AltText = pgtypeTextPtr(strings.TrimSpace(*req.AltText))
+	}
+	if req.Caption != nil {
+		params.Caption = pgtypeTextPtr(strings.TrimSpace(*req.Caption))
+	}
+
+	err := queries.UpdateProjectImageDetails(ctx, params)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update image")
+		return
+	}
+
+	updated, err := queries.GetProjectImageByID(ctx, imageID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+	variants, err := queries.ListProjectImageVariantsByImageID(ctx, imageID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, mapSQLCProjectImagesToModels([]sqlc.ProjectImage{updated}, variants)[0])
+}
+
+// SetProjectCoverImage makes an image the highlighted (cover) image of its project
+func SetProjectCoverImage(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+	imageID, ok := parseIDParam(c, "imageId", "Invalid project image ID")
+	if !ok {
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	img, ok := getProjectImageForProject(c, queries, projectID, imageID)
+	if !ok {
+		return
+	}
+
+	// Start transaction
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := queries.WithTx(tx)
+
+	if err := qtx.UnhighlightAllProjectImages(ctx, projectID); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to unhighlight images")
+		return
+	}
+
+	err = qtx.UpdateProjectImage(ctx, sqlc.UpdateProjectImageParams{
+		ID:          img.ID,
+		Name:        img.Name,
+		Url:         img.Url,
+		Order:       img.Order,
+		BlurHash:    img.BlurHash,
+		Highlighted: true,
+	})
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update image")
+		return
+	}
+
+	// A cover image without a blurhash gets one from the background workers
+	// (images still processing already have a job)
+	needsBlurHash := !img.BlurHash.Valid && img.ProcessingStatus != jobs.ImageProcessing
+	if needsBlurHash {
+		if _, err := qtx.EnqueueImageJob(ctx, img.ID); err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to update image")
+			return
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+		return
+	}
+
+	if needsBlurHash {
+		jobs.Notify()
+	}
+
+	respondWithProject(c, queries, projectID, http.StatusOK)
+}
+
+// ReorderProjectImages sets the order of a project's images from a list of all its image IDs
+func ReorderProjectImages(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	var req ReorderProjectImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	if !ensureProjectExists(c, queries, projectID) {
+		return
+	}
+
+	// Start transaction
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := queries.WithTx(tx)
+
+	images, err := qtx.ListProjectImagesByProjectID(ctx, projectID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	// The list must contain every image of the project exactly once
+	imageMap := make(map[int64]sqlc.ProjectImage, len(images))
+	for _, img := range images {
+		imageMap[img.ID] = img
+	}
+	seen := make(map[int64]bool, len(req.IDs))
+	for _, imgID := range req.IDs {
+		if _, exists := imageMap[imgID]; !exists || seen[imgID] {
+			ErrorResponse(c, http.StatusBadRequest, "Image IDs must list every image of the project exactly once")
+			return
+		}
+		seen[imgID] = true
+	}
+	if len(req.IDs) != len(images) {
+		ErrorResponse(c, http.StatusBadRequest, "Image IDs must list every image of the project exactly once")
+		return
+	}
+
+	for pos, imgID := range req.IDs {
+		img := imageMap[imgID]
+		err = qtx.UpdateProjectImage(ctx, sqlc.UpdateProjectImageParams{
+			ID:          img.ID,
+			Name:        img.Name,
+			Url:         img.Url,
+			Order:       int32(pos),
+			BlurHash:    img.BlurHash,
+			Highlighted: img.Highlighted,
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to update image")
+			return
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+		return
+	}
+
+	respondWithProject(c, queries, projectID, http.StatusOK)
+}
+
+// DeleteProjectImageByID removes a single image from a project
+func DeleteProjectImageByID(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+	imageID, ok := parseIDParam(c, "imageId", "Invalid project image ID")
+	if !ok {
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	img, ok := getProjectImageForProject(c, queries, projectID, imageID)
+	if !ok {
+		return
+	}
+
+	// Start transaction
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	released, err := deleteProjectImage(ctx, queries.WithTx(tx), img)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to delete project image")
+		return
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+		return
+	}
+
+	// Remove the file if no other image references it
+	if released != nil {
+		deleteStoredFiles(ctx, queries, []*releasedFile{released})
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// imageUploadError is returned by storeUploadedImage and recordUploadedImage together with the response it should produce
+type imageUploadError struct {
+	status  int
+	message string
+	err     error
+}
+
+func (e *imageUploadError) Error() string {
+	return e.message + ": " + e.err.Error()
+}
+
+func (e *imageUploadError) Unwrap() error {
+	return e.err
+}
+
+// respondUploadError writes the error response for an error returned by storeUploadedImage or recordUploadedImage
+func respondUploadError(c *gin.Context, err error) {
+	var uploadErr *imageUploadError
+	if !errors.As(err, &uploadErr) {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to create project image")
+		return
+	}
+	if uploadErr.status >= http.StatusInternalServerError {
+		ErrorResponse(c, uploadErr.status, uploadErr.message)
+		return
+	}
+	ErrorResponse(c, uploadErr.status, uploadErr.message, uploadErr.err.Error())
+}
+
+// storedImage is an uploaded file that was sanitized and written to storage, ready to be recorded
+type storedImage struct {
+	Filename string
+	URL      string
+	Meta     *storage.ImageMetadata
+}
+
+// storeUploadedImage strips the metadata of an uploaded file and writes it to storage.
+// It runs before the caller's transaction, so decoding and the backend write never hold one open;
+// files of a transaction that rolls back afterwards are unreferenced and left for the storage GC
+func storeUploadedImage(ctx context.Context, cfg *config.Config, file *uploadedFile) (*storedImage, error) {
+	// Open file
+	src, err := file.Open()
+	if err != nil {
+		return nil, &imageUploadError{http.StatusBadRequest, "Failed to open file", err}
+	}
+
+	// Read file data
+	fileData, err := io.ReadAll(src)
+	src.Close()
+	if err != nil {
+		return nil, &imageUploadError{http.StatusBadRequest, "Failed to read file", err}
+	}
+
+	// Apply EXIF orientation and strip metadata (GPS, camera info) before anything is stored
+	sanitized, meta, err := storage.SanitizeImage(fileData, cfg.Images)
+	if err != nil {
+		return nil, &imageUploadError{http.StatusBadRequest, "Failed to process image", err}
+	}
+
+	// Save file, reusing the hash computed while streaming if sanitizing didn't change anything
+	var url string
+	if bytes.Equal(sanitized, fileData) {
+		url, err = storage.SaveFileWithHash(ctx, sanitized, file.Filename, file.SHA1)
+	} else {
+		url, err = storage.SaveFile(ctx, sanitized, file.Filename)
+	}
+	if err != nil {
+		return nil, &imageUploadError{http.StatusBadRequest, "Failed to save file", err}
+	}
+
+	return &storedImage{Filename: file.Filename, URL: url, Meta: meta}, nil
+}
+
+// storeUploadedImages stores several uploaded files with storeUploadedImage, in order
+func storeUploadedImages(ctx context.Context, cfg *config.Config, files []*uploadedFile) ([]*storedImage, error) {
+	stored := make([]*storedImage, len(files))
+	for i, file := range files {
+		img, err := storeUploadedImage(ctx, cfg, file)
+		if err != nil {
+			return nil, err
+		}
+		stored[i] = img
+	}
+	return stored, nil
+}
+
+// recordUploadedImage records a stored file as an image of the given project, queueing the rest
+// of the processing for the background workers
+func recordUploadedImage(ctx context.Context, qtx *sqlc.Queries, cfg *config.Config, projectID int64, stored *storedImage, order int, highlighted bool) (sqlc.ProjectImage, error) {
+	// Create project image
+	capturedAt, gpsLatitude, gpsLongitude := captureMetadataParams(stored.Meta, cfg)
+	img, err := qtx.CreateProjectImage(ctx, sqlc.CreateProjectImageParams{
+		Name:             stored.Filename,
+		Url:              stored.URL,
+		ProjectID:        projectID,
+		Order:            int32(order),
+		Highlighted:      highlighted,
+		CapturedAt:       capturedAt,
+		GpsLatitude:      gpsLatitude,
+		GpsLongitude:     gpsLongitude,
+		ProcessingStatus: jobs.ImageProcessing,
+	})
+	if err != nil {
+		return sqlc.ProjectImage{}, &imageUploadError{http.StatusInternalServerError, "Failed to create project image", err}
+	}
+
+	// Reference the stored file
+	if _, err := qtx.AcquireStoredBlob(ctx, stored.URL); err != nil {
+		return sqlc.ProjectImage{}, &imageUploadError{http.StatusInternalServerError, "Failed to create project image", err}
+	}
+
+	// Blurhash, image info and variants are generated by the background workers
+	if _, err := qtx.EnqueueImageJob(ctx, img.ID); err != nil {
+		return sqlc.ProjectImage{}, &imageUploadError{http.StatusInternalServerError, "Failed to create project image", err}
+	}
+
+	return img, nil
+}
+
+// getUploadedFiles returns the uploaded files of a form, supporting both files[]
+// and files[0], files[1], ... (ordered by index)
+func getUploadedFiles(form *uploadForm) []*uploadedFile {
+	// First, try files[] format (backward compatibility)
+	if filesArray, ok := form.File["files[]"]; ok {
+		return filesArray
+	}
+
+	// Try files[0], files[1], etc. format
+	fileKeyRegex := regexp.MustCompile(`^files\[(\d+)\]$`)
+	filesMap := make(map[int]*uploadedFile)
+	for key, fileHeaders := range form.File {
+		if matches := fileKeyRegex.FindStringSubmatch(key); matches != nil {
+			if len(fileHeaders) > 0 {
+				index, _ := strconv.Atoi(matches[1])
+				filesMap[index] = fileHeaders[0]
+			}
+		}
+	}
+
+	// Convert map to sorted slice
+	indices := make([]int, 0, len(filesMap))
+	for idx := range filesMap {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+	files := make([]*uploadedFile, len(indices))
+	for i, idx := range indices {
+		files[i] = filesMap[idx]
+	}
+	return files
+}
+
+// nextImageOrder returns the order value that places a new image after all existing images of a project
+func nextImageOrder(ctx context.Context, qtx *sqlc.Queries, projectID int64) (int, error) {
+	existing, err := qtx.ListProjectImagesByProjectID(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	nextOrder := 0
+	for _, img := range existing {
+		if int(img.Order) >= nextOrder {
+			nextOrder = int(img.Order) + 1
+		}
+	}
+	return nextOrder, nil
+}
+
+// parseIDParam parses a numeric path parameter, writing a 400 response if it is invalid
+func parseIDParam(c *gin.Context, name, message string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, message)
+		return 0, false
+	}
+	return id, true
+}
+
+// ensureProjectExists writes a 404/500 response and returns false if the project can't be loaded
+func ensureProjectExists(c *gin.Context, queries *sqlc.Queries, id int64) bool {
+	_, err := queries.GetProjectByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusNotFound, "Project not found")
+			return false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return false
+	}
+	return true
+}
+
+// getProjectImageForProject loads an image and checks that it belongs to the project,
+// writing a 404/500 response if not
+func getProjectImageForProject(c *gin.Context, queries *sqlc.Queries, projectID, imageID int64) (sqlc.ProjectImage, bool) {
+	img, err := queries.GetProjectImageByID(c.Request.Context(), imageID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusNotFound, "Project image not found")
+			return sqlc.ProjectImage{}, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return sqlc.ProjectImage{}, false
+	}
+	if img.ProjectID != projectID {
+		ErrorResponse(c, http.StatusNotFound, "Project image not found")
+		return sqlc.ProjectImage{}, false
+	}
+	return img, true
+}
+
+// respondWithProject writes the project with its images
+func respondWithProject(c *gin.Context, queries *sqlc.Queries, id int64, status int) {
+	ctx := c.Request.Context()
+
+	project, err := queries.GetProjectByID(ctx, id)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	images, err := loadProjectImages(ctx, queries, id)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	projectModel := mapSQLCProjectToModel(project)
+	projectModel.Images = images
+	if err := attachProjectCategory(ctx, queries, &projectModel); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	SuccessResponse(c, status, projectModel)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/models"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// ReorderProjectsRequest is the body of PUT /api/projects/order
+type ReorderProjectsRequest struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+// MoveProjectRequest is the body of PUT /api/projects/:id/move.
+// Exactly one of Before and After is set: the ID of the project to place it next to
+type MoveProjectRequest struct {
+	Before *int64 `json:"before"`
+	After  *int64 `json:"after"`
+}
+
+// ReorderProjects sets the display order of all projects at once
+func ReorderProjects(c *gin.Context) {
+	var req ReorderProjectsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	// Start transaction
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := queries.WithTx(tx)
+
+	projectIDs, err := qtx.ListProjectIDsByOrder(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	// The list must contain every project exactly once
+	exists := make(map[int64]bool, len(projectIDs))
+	for _, projectID := range projectIDs {
+		exists[projectID] = true
+	}
+	seen := make(map[int64]bool, len(req.IDs))
+	for _, projectID := range req.IDs {
+		if !exists[projectID] || seen[projectID] {
+			ErrorResponse(c, http.StatusBadRequest, "Project IDs must list every project exactly once")
+			return
+		}
+		seen[projectID] = true
+	}
+	if len(req.IDs) != len(projectIDs) {
+		ErrorResponse(c, http.StatusBadRequest, "Project IDs must list every project exactly once")
+		return
+	}
+
+	if err := qtx.SetProjectOrder(ctx, req.IDs); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update project order")
+		return
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+		return
+	}
+
+	respondWithProjectOrder(c, req.IDs)
+}
+
+// MoveProject places a project right before or after another one (drag and drop).
+// The whole display order is renumbered, so projects sharing an order value get distinct ones
+func MoveProject(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	var req MoveProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+	if (req.Before == nil) == (req.After == nil) {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", "exactly one of before or after is required")
+		return
+	}
+	target := req.Before
+	if target == nil {
+		target = req.After
+	}
+	if *target == id {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid request body", "a project cannot be moved next to itself")
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	// Start transaction
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := queries.WithTx(tx)
+
+	projectIDs, err := qtx.ListProjectIDsByOrder(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	from := slices.Index(projectIDs, id)
+	if from < 0 {
+		ErrorResponse(c, http.StatusNotFound, "Project not found")
+		return
+	}
+	projectIDs = slices.Delete(projectIDs, from, from+1)
+
+	to := slices.Index(projectIDs, *target)
+	if to < 0 {
+		ErrorResponse(c, http.StatusBadRequest, "Unknown project", "before/after must be the ID of another project")
+		return
+	}
+	if req.After != nil {
+		to++
+	}
+	projectIDs = slices.Insert(projectIDs, to, id)
+
+	if err := qtx.SetProjectOrder(ctx, projectIDs); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to update project order")
+		return
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+		return
+	}
+
+	respondWithProjectOrder(c, projectIDs)
+}
+
+// respondWithProjectOrder writes the new display order: every project ID with its order
+func respondWithProjectOrder(c *gin.Context, projectIDs []int64) {
+	order := make([]models.ProjectOrder, len(projectIDs))
+	for i, projectID := range projectIDs {
+		order[i] = models.ProjectOrder{ID: projectID, Order: i}
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"data": order})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/slug"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxSlugSuffix bounds the search for a free "<slug>-<n>"
+const maxSlugSuffix = 1000
+
+var errSlugTaken = errors.New("slug already in use")
+
+// GetPublicProjectBySlug returns a single published project by its slug.
+// Old slugs of renamed projects answer with a 301 to the current one (only while it is published).
+func GetPublicProjectBySlug(c *gin.Context) {
+	projectSlug := c.Param("slug")
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	project, err := queries.GetPublishedProjectBySlug(ctx, projectSlug)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		current, err := queries.GetRedirectedProjectSlug(ctx, projectSlug)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				ErrorResponse(c, http.StatusNotFound, "Project not found")
+				return
+			}
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+		c.Redirect(http.StatusMovedPermanently, "/api/pub/projects/by-slug/"+current)
+		return
+	}
+
+	images, err := loadProjectImages(ctx, queries, project.ID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	projectModel := mapSQLCProjectToModel(project)
+	projectModel.Images = hideInternalImageData(images)
+	if err := attachProjectCategory(ctx, queries, &projectModel); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, projectModel)
+}
+
+// getFormSlug reads the optional "slug" form field, normalized with slug.Make.
+// It responds with 400 and returns false when the field has no usable characters.
+func getFormSlug(c *gin.Context, form *uploadForm) (string, bool) {
+	raw := getFormValue(form, "slug")
+	if raw == "" {
+		return "", true
+	}
+
+	s := slug.Make(raw)
+	if s == "" {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid slug", "slug must contain letters or digits")
+		return "", false
+	}
+	return s, true
+}
+
+// assignProjectSlug picks the slug for a project (projectID is 0 for new projects).
+// A slug requested by the admin is used as is and fails with errSlugTaken when another
+// project owns it; otherwise the slug is derived from name, adding -2, -3, ... until it is free.
+func assignProjectSlug(ctx context.Context, queries *sqlc.Queries, projectID int64, requested, name string) (string, error) {
+	if requested != "" {
+		taken, err := queries.ProjectSlugTaken(ctx, sqlc.ProjectSlugTakenParams{Slug: requested, ID: projectID})
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", errSlugTaken
+		}
+		return requested, nil
+	}
+
+	base := slug.Make(name)
+	if base == "" {
+		base = "project"
+	}
+
+	candidate := base
+	for n := 2; n <= maxSlugSuffix; n++ {
+		taken, err := queries.ProjectSlugTaken(ctx, sqlc.ProjectSlugTakenParams{Slug: candidate, ID: projectID})
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+	return "", fmt.Errorf("no free slug for %q", base)
+}
+
+// recordSlugChange keeps oldSlug pointing at the project after it moved to newSlug
+func recordSlugChange(ctx context.Context, queries *sqlc.Queries, projectID int64, oldSlug, newSlug string) error {
+	// Taking back one of the project's previous slugs: it is no longer a redirect
+	if err := queries.DeleteProjectSlugRedirect(ctx, newSlug); err != nil {
+		return err
+	}
+	return queries.UpsertProjectSlugRedirect(ctx, sqlc.UpsertProjectSlugRedirectParams{
+		Slug:      oldSlug,
+		ProjectID: projectID,
+	})
+}
+
+// respondSlugError maps errors from assignProjectSlug to responses
+func respondSlugError(c *gin.Context, err error) {
+	if errors.Is(err, errSlugTaken) {
+		ErrorResponse(c, http.StatusConflict, "Slug already in use")
+		return
+	}
+	ErrorResponse(c, http.StatusInternalServerError, "Failed to assign slug")
+}
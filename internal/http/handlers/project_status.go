@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Project lifecycle; only published projects are returned by /api/pub
+const (
+	projectDraft     = "draft"
+	projectPublished = "published"
+	projectArchived  = "archived"
+)
+
+// projectStatuses lists the valid values of projects.status
+var projectStatuses = []string{projectDraft, projectPublished, projectArchived}
+
+// projectTransition is a status change and the statuses it can be applied to
+type projectTransition struct {
+	to   string
+	from []string
+}
+
+var (
+	publishProject   = projectTransition{to: projectPublished, from: []string{projectDraft, projectArchived}}
+	unpublishProject = projectTransition{to: projectDraft, from: []string{projectPublished}}
+	archiveProject   = projectTransition{to: projectArchived, from: []string{projectDraft, projectPublished}}
+	restoreProject   = projectTransition{to: projectDraft, from: []string{projectArchived}}
+)
+
+// PublishProject makes a draft or archived project public
+func PublishProject(c *gin.Context) {
+	transitionProject(c, publishProject)
+}
+
+// UnpublishProject moves a published project back to draft
+func UnpublishProject(c *gin.Context) {
+	transitionProject(c, unpublishProject)
+}
+
+// ArchiveProject hides a draft or published project from the public site
+func ArchiveProject(c *gin.Context) {
+	transitionProject(c, archiveProject)
+}
+
+// RestoreProject moves an archived project back to draft
+func RestoreProject(c *gin.Context) {
+	transitionProject(c, restoreProject)
+}
+
+// transitionProject applies t to the project in the URL and responds with the updated project.
+// Projects whose current status is not in t.from are left unchanged (409).
+func transitionProject(c *gin.Context, t projectTransition) {
+	id, ok := parseIDParam(c, "id", "Invalid project ID")
+	if !ok {
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	_, err := queries.UpdateProjectStatus(ctx, sqlc.UpdateProjectStatusParams{
+		ID:      id,
+		Status:  t.to,
+		Column3: t.from,
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to update project status")
+			return
+		}
+
+		// Either the project does not exist or its status does not allow the transition
+		project, err := queries.GetProjectByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				ErrorResponse(c, http.StatusNotFound, "Project not found")
+				return
+			}
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+		ErrorResponse(c, http.StatusConflict, "Invalid status transition",
+			fmt.Sprintf("cannot change a %s project to %s", project.Status, t.to))
+		return
+	}
+
+	respondWithProject(c, queries, id, http.StatusOK)
+}
+
+// isProjectStatus reports whether s is a valid project status
+func isProjectStatus(s string) bool {
+	for _, status := range projectStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -1,17 +1,20 @@
 package handlers
 
 import (
+	"context"
 	"errors"
-	"io"
-	"mime/multipart"
+	"fmt"
+	"math"
 	"net/http"
-	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/jobs"
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/models"
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/storage"
@@ -20,8 +23,11 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// GetProjects returns paginated projects (10 per page)
-// Supports query parameters: ?search=term&sort_by=field&sort_order=asc|desc
+// GetProjects returns paginated projects (?page=&per_page=, 10 per page by default)
+// Supports query parameters: ?search=term&sort_by=field&sort_order=asc|desc&status=draft|published|archived
+// and the filters ?location=city&year=2023&service=name. The search is full-text (see searchQuery):
+// matches have a highlighted snippet and, without sort_by, come best first (page mode).
+// With ?cursor= or ?limit= the list is paginated by cursor instead (see parseCursorRequest)
 func GetProjects(c *gin.Context) {
 	pageStr := c.DefaultQuery("page", "1")
 	page, err := strconv.Atoi(pageStr)
@@ -31,34 +37,115 @@ func GetProjects(c *gin.Context) {
 
 	// Parse query parameters using modular helper
 	params := ParseQueryParams(c)
-	
+	search := searchQuery(params.Search)
+
+	status := strings.ToLower(strings.TrimSpace(c.Query("status")))
+	if status != "" && !isProjectStatus(status) {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid status", "status must be one of: "+strings.Join(projectStatuses, ", "))
+		return
+	}
+
+	location := strings.TrimSpace(c.Query("location"))
+	service := strings.TrimSpace(c.Query("service"))
+	var year int
+	if yearStr := c.Query("year"); yearStr != "" {
+		year, err = strconv.Atoi(yearStr)
+		if err != nil || year < 1 {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid year")
+			return
+		}
+	}
+
 	// Validate sort parameters
 	allowedSortFields := []string{"order", "name", "created_at"}
 	sortBy := ValidateSortBy(params.SortBy, allowedSortFields)
 	sortOrder := ValidateSortOrder(params.SortOrder)
 
+	keysetSort := sortBy
+	if keysetSort == "" {
+		keysetSort = "order"
+	}
+	cursorReq, cursorMode, ok := parseCursorRequest(c, keysetSort+":"+sortOrder, 10, maxPageLimit)
+	if !ok {
+		return
+	}
+
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
-	perPage := 10
+
+	if cursorMode {
+		desc := sortOrder == "desc"
+		projects, err := queries.ListProjectsKeyset(ctx, sqlc.ListProjectsKeysetParams{
+			Column1:  search,
+			Column2:  status,
+			Column3:  location,
+			Column4:  int32(year),
+			Column5:  service,
+			Column6:  keysetSort,
+			Column7:  cursorReq.fetchDescending(desc),
+			Column8:  cursorReq.afterInt(),
+			Column9:  cursorReq.afterText(),
+			Column10: cursorReq.afterTime(),
+			Column11: cursorReq.afterID(),
+			Limit:    int32(cursorReq.Limit + 1),
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		projects, next, prev := paginateCursor(cursorReq, projects, projectCursor(keysetSort))
+		projectModels, err := loadProjectModels(ctx, queries, projects)
+		if err != nil {
+			c.Error(err)
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+		if err := attachProjectSnippets(ctx, queries, search, projectModels); err != nil {
+			c.Error(err)
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		SuccessResponse(c, http.StatusOK, models.CursorPaginationResponse{
+			Data:       projectModels,
+			Limit:      cursorReq.Limit,
+			NextCursor: next,
+			PrevCursor: prev,
+		})
+		return
+	}
+
+	perPage := ParsePerPage(c, 10, maxPageLimit)
 	offset := (page - 1) * perPage
 
 	// Use the new query with search and sort support
 	projects, err := queries.ListProjectsWithSearch(ctx, sqlc.ListProjectsWithSearchParams{
-		Column1: params.Search,
+		Column1: search,
 		Column2: sortBy,
 		Column3: sortOrder,
 		Limit:   int32(perPage),
 		Offset:  int32(offset),
+		Column6: status,
+		Column7: location,
+		Column8: int32(year),
+		Column9: service,
 	})
 	if err != nil {
 		ErrorResponse(c, http.StatusInternalServerError, "Database error")
 		return
 	}
 
-	// Get total count with search filter
+	// Get total count with search and filters
 	var total int64
-	if params.Search != "" {
-		total, err = queries.CountProjectsWithSearch(ctx, params.Search)
+	if search != "" || status != "" || location != "" || year != 0 || service != "" {
+		total, err = queries.CountProjectsWithSearch(ctx, sqlc.CountProjectsWithSearchParams{
+			Column1: search,
+			Column2: status,
+			Column3: location,
+			Column4: int32(year),
+			Column5: service,
+		})
 	} else {
 		total, err = queries.CountProjects(ctx)
 	}
@@ -67,15 +154,17 @@ func GetProjects(c *gin.Context) {
 		return
 	}
 
-	// Map sqlc projects to models and load images
-	projectModels := make([]models.Project, len(projects))
-	for i, p := range projects {
-		projectModels[i] = mapSQLCProjectToModel(p)
-		// Load images for each project
-		images, err := queries.ListProjectImagesByProjectID(ctx, p.ID)
-		if err == nil {
-			projectModels[i].Images = mapSQLCProjectImagesToModels(images)
-		}
+	// Map sqlc projects to models with their images and categories (and search snippets)
+	projectModels, err := loadProjectModels(ctx, queries, projects)
+	if err != nil {
+		c.Error(err)
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if err := attachProjectSnippets(ctx, queries, search, projectModels); err != nil {
+		c.Error(err)
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
 	}
 
 	SuccessResponse(c, http.StatusOK, models.PaginationResponse{
@@ -110,14 +199,18 @@ func GetProject(c *gin.Context) {
 	}
 
 	// Get project images
-	images, err := queries.ListProjectImagesByProjectID(ctx, id)
+	images, err := loadProjectImages(ctx, queries, id)
 	if err != nil {
 		ErrorResponse(c, http.StatusInternalServerError, "Database error")
 		return
 	}
 
 	projectModel := mapSQLCProjectToModel(project)
-	projectModel.Images = mapSQLCProjectImagesToModels(images)
+	projectModel.Images = images
+	if err := attachProjectCategory(ctx, queries, &projectModel); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
 
 	SuccessResponse(c, http.StatusOK, projectModel)
 }
@@ -125,12 +218,13 @@ func GetProject(c *gin.Context) {
 // CreateProject creates a new project with multipart file uploads
 func CreateProject(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Parse multipart form
-		form, err := c.MultipartForm()
+		// Stream the multipart form to temporary files
+		form, err := parseUploadForm(c, cfg.Uploads)
 		if err != nil {
-			ErrorResponse(c, http.StatusBadRequest, "Invalid multipart form", err.Error())
+			respondUploadError(c, err)
 			return
 		}
+		defer form.RemoveAll()
 
 		// Extract form fields
 		name := getFormValue(form, "name")
@@ -139,7 +233,31 @@ func CreateProject(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		category := getFormValue(form, "category")
+		requestedSlug, ok := getFormSlug(c, form)
+		if !ok {
+			return
+		}
+
+		// New projects stay hidden from the public site until published
+		status := getFormValue(form, "status")
+		if status == "" {
+			status = projectDraft
+		}
+		if status != projectDraft && status != projectPublished {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid status", "status must be draft or published")
+			return
+		}
+
+		publishAt, unpublishAt, ok := getFormSchedule(c, form)
+		if !ok {
+			return
+		}
+
+		details, ok := getFormProjectDetails(c, form)
+		if !ok {
+			return
+		}
+
 		client := getFormValue(form, "client")
 		orderStr := getFormValue(form, "order")
 		order := 0
@@ -154,38 +272,8 @@ func CreateProject(cfg *config.Config) gin.HandlerFunc {
 		}
 
 		// Get uploaded files - support both files[] and files[0], files[1], etc.
-		var files []*multipart.FileHeader
-		
-		// First, try files[] format (backward compatibility)
-		if filesArray, ok := form.File["files[]"]; ok {
-			files = filesArray
-		} else {
-			// Try files[0], files[1], etc. format
-			fileKeyRegex := regexp.MustCompile(`^files\[(\d+)\]$`)
-			filesMap := make(map[int]*multipart.FileHeader)
-			for key, fileHeaders := range form.File {
-				if matches := fileKeyRegex.FindStringSubmatch(key); matches != nil {
-					if len(fileHeaders) > 0 {
-						index, _ := strconv.Atoi(matches[1])
-						filesMap[index] = fileHeaders[0]
-					}
-				}
-			}
-			
-			// Convert map to sorted slice
-			if len(filesMap) > 0 {
-				indices := make([]int, 0, len(filesMap))
-				for idx := range filesMap {
-					indices = append(indices, idx)
-				}
-				sort.Ints(indices)
-				files = make([]*multipart.FileHeader, len(indices))
-				for i, idx := range indices {
-					files[i] = filesMap[idx]
-				}
-			}
-		}
-		
+		files := getUploadedFiles(form)
+
 		if len(files) == 0 {
 			ErrorResponse(c, http.StatusBadRequest, "At least one file is required")
 			return
@@ -194,6 +282,17 @@ func CreateProject(cfg *config.Config) gin.HandlerFunc {
 		queries := sqlc.New(db.Pool)
 		ctx := c.Request.Context()
 
+		categoryID, ok := getFormCategory(c, queries, form)
+		if !ok {
+			return
+		}
+
+		stored, err := storeUploadedImages(ctx, cfg, files)
+		if err != nil {
+			respondUploadError(c, err)
+			return
+		}
+
 		// Start transaction
 		tx, err := db.Pool.Begin(ctx)
 		if err != nil {
@@ -204,15 +303,30 @@ func CreateProject(cfg *config.Config) gin.HandlerFunc {
 
 		qtx := queries.WithTx(tx)
 
+		projectSlug, err := assignProjectSlug(ctx, qtx, 0, requestedSlug, name)
+		if err != nil {
+			respondSlugError(c, err)
+			return
+		}
+
 		// Create project in database
 		highlighted := highlightImageIndex >= 0 && highlightImageIndex < len(files)
 		project, err := qtx.CreateProject(ctx, sqlc.CreateProjectParams{
-			Status:      1,
-			Name:        name,
-			Category:    pgtypeTextPtr(category),
-			Client:      pgtypeTextPtr(client),
-			Order:       int32(order),
-			Highlighted: highlighted,
+			Status:         status,
+			Name:           name,
+			CategoryID:     categoryID,
+			Client:         pgtypeTextPtr(client),
+			Order:          int32(order),
+			Highlighted:    highlighted,
+			Slug:           projectSlug,
+			PublishAt:      pgtypeTimestamptzPtr(publishAt),
+			UnpublishAt:    pgtypeTimestamptzPtr(unpublishAt),
+			Description:    details.Description,
+			Location:       details.Location,
+			CompletionYear: details.CompletionYear,
+			AreaM2:         details.AreaM2,
+			DurationMonths: details.DurationMonths,
+			Services:       details.Services,
 		})
 		if err != nil {
 			ErrorResponse(c, http.StatusInternalServerError, "Failed to create project")
@@ -225,52 +339,13 @@ func CreateProject(cfg *config.Config) gin.HandlerFunc {
 			_ = qtx.UnhighlightAllProjectImages(ctx, project.ID)
 		}
 
-		// Save files, generate blurhash, create project_images records
-		for i, file := range files {
-			// Open file
-			src, err := file.Open()
-			if err != nil {
-				ErrorResponse(c, http.StatusBadRequest, "Failed to open file", err.Error())
-				return
-			}
-
-			// Read file data
-			fileData, err := io.ReadAll(src)
-			src.Close()
-			if err != nil {
-				ErrorResponse(c, http.StatusBadRequest, "Failed to read file", err.Error())
-				return
-			}
-
-			// Save file
-			url, err := storage.SaveFile(fileData, file.Filename, cfg.StoragePath)
-			if err != nil {
-				ErrorResponse(c, http.StatusBadRequest, "Failed to save file", err.Error())
-				return
-			}
-
-			// Generate blurhash (especially important for highlighted images)
-			filePath := filepath.Join(cfg.StoragePath, "public", "img", filepath.Base(url))
-			blurHash, err := storage.GenerateBlurHash(filePath)
-			var blurHashPtr pgtype.Text
-			if err == nil {
-				blurHashPtr = pgtype.Text{String: blurHash, Valid: true}
-			}
-
+		// Create project_images records for the stored files
+		for i, img := range stored {
 			// Determine if this image should be highlighted
 			isHighlighted := highlightImageIndex >= 0 && i == highlightImageIndex
 
-			// Create project image
-			_, err = qtx.CreateProjectImage(ctx, sqlc.CreateProjectImageParams{
-				Name:        file.Filename,
-				Url:         url,
-				ProjectID:   project.ID,
-				Order:       int32(i),
-				BlurHash:    blurHashPtr,
-				Highlighted: isHighlighted,
-			})
-			if err != nil {
-				ErrorResponse(c, http.StatusInternalServerError, "Failed to create project image")
+			if _, err := recordUploadedImage(ctx, qtx, cfg, project.ID, img, i, isHighlighted); err != nil {
+				respondUploadError(c, err)
 				return
 			}
 		}
@@ -281,10 +356,14 @@ func CreateProject(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		// Start processing the uploaded images
+		jobs.Notify()
+
 		// Get project with images
-		images, _ := queries.ListProjectImagesByProjectID(ctx, project.ID)
+		images, _ := loadProjectImages(ctx, queries, project.ID)
 		projectModel := mapSQLCProjectToModel(project)
-		projectModel.Images = mapSQLCProjectImagesToModels(images)
+		projectModel.Images = images
+		_ = attachProjectCategory(ctx, queries, &projectModel)
 
 		SuccessResponse(c, http.StatusCreated, projectModel)
 	}
@@ -300,16 +379,28 @@ func UpdateProject(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// Parse multipart form
-		form, err := c.MultipartForm()
+		// Stream the multipart form to temporary files
+		form, err := parseUploadForm(c, cfg.Uploads)
 		if err != nil {
-			ErrorResponse(c, http.StatusBadRequest, "Invalid multipart form", err.Error())
+			respondUploadError(c, err)
 			return
 		}
+		defer form.RemoveAll()
 
 		// Extract form fields
 		name := getFormValue(form, "name")
-		category := getFormValue(form, "category")
+		requestedSlug, ok := getFormSlug(c, form)
+		if !ok {
+			return
+		}
+		publishAt, unpublishAt, ok := getFormSchedule(c, form)
+		if !ok {
+			return
+		}
+		details, ok := getFormProjectDetails(c, form)
+		if !ok {
+			return
+		}
 		client := getFormValue(form, "client")
 		orderStr := getFormValue(form, "order")
 		order := 0
@@ -337,6 +428,11 @@ func UpdateProject(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		categoryID, ok := getFormCategory(c, queries, form)
+		if !ok {
+			return
+		}
+
 		// Get current image IDs
 		currentImages, err := queries.ListProjectImageIDsByProjectID(ctx, id)
 		if err != nil {
@@ -376,7 +472,7 @@ func UpdateProject(cfg *config.Config) gin.HandlerFunc {
 
 		// Parse new files from form.File
 		// Files are sent as files[0], files[1], etc.
-		newFilesMap := make(map[int]*multipart.FileHeader) // index -> file
+		newFilesMap := make(map[int]*uploadedFile) // index -> file
 		fileKeyRegex := regexp.MustCompile(`^files\[(\d+)\]$`)
 		for key, files := range form.File {
 			if matches := fileKeyRegex.FindStringSubmatch(key); matches != nil {
@@ -390,6 +486,17 @@ func UpdateProject(cfg *config.Config) gin.HandlerFunc {
 			}
 		}
 
+		// Store new files before the transaction
+		newStoredMap := make(map[int]*storedImage, len(newFilesMap)) // index -> stored file
+		for index, file := range newFilesMap {
+			stored, err := storeUploadedImage(ctx, cfg, file)
+			if err != nil {
+				respondUploadError(c, err)
+				return
+			}
+			newStoredMap[index] = stored
+		}
+
 		// Start transaction
 		tx, err := db.Pool.Begin(ctx)
 		if err != nil {
@@ -407,83 +514,78 @@ func UpdateProject(cfg *config.Config) gin.HandlerFunc {
 			highlighted = highlightImageIndex < totalImagesAfterUpdate
 		}
 
+		// Keep the slug unless the admin sets one or the project is renamed;
+		// the previous slug then redirects to the new one
+		projectSlug := project.Slug
+		if requestedSlug != "" || (name != "" && name != project.Name) {
+			projectSlug, err = assignProjectSlug(ctx, qtx, id, requestedSlug, name)
+			if err != nil {
+				respondSlugError(c, err)
+				return
+			}
+		}
+		if projectSlug != project.Slug {
+			if err := recordSlugChange(ctx, qtx, id, project.Slug, projectSlug); err != nil {
+				ErrorResponse(c, http.StatusInternalServerError, "Failed to update project")
+				return
+			}
+		}
+
 		// Update project
 		err = qtx.UpdateProject(ctx, sqlc.UpdateProjectParams{
-			ID:          id,
-			Status:      int16(project.Status),
-			Name:        name,
-			Category:    pgtypeTextPtr(category),
-			Client:      pgtypeTextPtr(client),
-			Order:       int32(order),
-			Highlighted: highlighted,
+			ID:             id,
+			Status:         project.Status,
+			Name:           name,
+			CategoryID:     categoryID,
+			Client:         pgtypeTextPtr(client),
+			Order:          int32(order),
+			Highlighted:    highlighted,
+			Slug:           projectSlug,
+			PublishAt:      pgtypeTimestamptzPtr(publishAt),
+			UnpublishAt:    pgtypeTimestamptzPtr(unpublishAt),
+			Description:    details.Description,
+			Location:       details.Location,
+			CompletionYear: details.CompletionYear,
+			AreaM2:         details.AreaM2,
+			DurationMonths: details.DurationMonths,
+			Services:       details.Services,
 		})
 		if err != nil {
 			ErrorResponse(c, http.StatusInternalServerError, "Failed to update project")
 			return
 		}
 
-		// Delete removed images
+		// Delete removed images, files are only removed once no other image references them
+		var releasedFiles []*releasedFile
 		for _, imgID := range imagesToDelete {
-			// Get image to get URL for file deletion
 			img, err := qtx.GetProjectImageByID(ctx, imgID)
-			if err == nil {
-				// Delete from database
-				err = qtx.DeleteProjectImage(ctx, imgID)
-				if err == nil {
-					// Delete file from filesystem
-					storage.DeleteFile(img.Url, cfg.StoragePath)
-				}
-			}
-		}
-
-		// Add new files and track their IDs by index
-		newImageIDsMap := make(map[int]int64) // index -> new image ID
-		for index := 0; index < 1000; index++ { // Iterate through indices (reasonable max)
-			file, isNewFile := newFilesMap[index]
-			if !isNewFile {
-				continue
-			}
-
-			// Open file
-			src, err := file.Open()
 			if err != nil {
-				ErrorResponse(c, http.StatusBadRequest, "Failed to open file", err.Error())
+				ErrorResponse(c, http.StatusInternalServerError, "Database error")
 				return
 			}
 
-			// Read file data
-			fileData, err := io.ReadAll(src)
-			src.Close()
+			released, err := deleteProjectImage(ctx, qtx, img)
 			if err != nil {
-				ErrorResponse(c, http.StatusBadRequest, "Failed to read file", err.Error())
+				ErrorResponse(c, http.StatusInternalServerError, "Failed to delete project image")
 				return
 			}
-
-			// Save file
-			url, err := storage.SaveFile(fileData, file.Filename, cfg.StoragePath)
-			if err != nil {
-				ErrorResponse(c, http.StatusBadRequest, "Failed to save file", err.Error())
-				return
+			if released != nil {
+				releasedFiles = append(releasedFiles, released)
 			}
+		}
 
-			// Generate blurhash
-			filePath := filepath.Join(cfg.StoragePath, "public", "img", filepath.Base(url))
-			blurHash, err := storage.GenerateBlurHash(filePath)
-			var blurHashPtr pgtype.Text
-			if err == nil {
-				blurHashPtr = pgtype.Text{String: blurHash, Valid: true}
+		// Add new files and track their IDs by index
+		newImageIDsMap := make(map[int]int64)   // index -> new image ID
+		for index := 0; index < 1000; index++ { // Iterate through indices (reasonable max)
+			stored, isNewFile := newStoredMap[index]
+			if !isNewFile {
+				continue
 			}
 
 			// Create project image (order will be set later)
-			newImg, err := qtx.CreateProjectImage(ctx, sqlc.CreateProjectImageParams{
-				Name:      file.Filename,
-				Url:       url,
-				ProjectID: id,
-				Order:     0, // Will be updated below
-				BlurHash:  blurHashPtr,
-			})
+			newImg, err := recordUploadedImage(ctx, qtx, cfg, id, stored, 0, false)
 			if err != nil {
-				ErrorResponse(c, http.StatusInternalServerError, "Failed to create project image")
+				respondUploadError(c, err)
 				return
 			}
 			newImageIDsMap[index] = newImg.ID
@@ -543,6 +645,7 @@ func UpdateProject(cfg *config.Config) gin.HandlerFunc {
 		sort.Ints(sortedIndices)
 
 		// Update orders and highlighted flags
+		enqueuedJobs := len(newImageIDsMap) > 0
 		for finalPos, idx := range sortedIndices {
 			imgID := finalOrderMap[idx]
 			img, exists := imageMap[imgID]
@@ -560,13 +663,14 @@ func UpdateProject(cfg *config.Config) gin.HandlerFunc {
 				isHighlighted = img.Highlighted
 			}
 
-			// Generate blurhash for highlighted image if not already set
-			if isHighlighted && !img.BlurHash.Valid {
-				filePath := filepath.Join(cfg.StoragePath, "public", "img", filepath.Base(img.Url))
-				blurHash, err := storage.GenerateBlurHash(filePath)
-				if err == nil {
-					img.BlurHash = pgtype.Text{String: blurHash, Valid: true}
+			// A highlighted image without a blurhash gets one from the background workers
+			// (images still processing already have a job)
+			if isHighlighted && !img.BlurHash.Valid && img.ProcessingStatus != jobs.ImageProcessing {
+				if _, err := qtx.EnqueueImageJob(ctx, img.ID); err != nil {
+					ErrorResponse(c, http.StatusInternalServerError, "Failed to update image")
+					return
 				}
+				enqueuedJobs = true
 			}
 
 			err = qtx.UpdateProjectImage(ctx, sqlc.UpdateProjectImageParams{
@@ -589,11 +693,20 @@ func UpdateProject(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		// Remove files that are no longer referenced
+		deleteStoredFiles(ctx, queries, releasedFiles)
+
+		// Start processing the uploaded images
+		if enqueuedJobs {
+			jobs.Notify()
+		}
+
 		// Get updated project with images
 		updatedProject, _ := queries.GetProjectByID(ctx, id)
-		images, _ := queries.ListProjectImagesByProjectID(ctx, id)
+		images, _ := loadProjectImages(ctx, queries, id)
 		projectModel := mapSQLCProjectToModel(updatedProject)
-		projectModel.Images = mapSQLCProjectImagesToModels(images)
+		projectModel.Images = images
+		_ = attachProjectCategory(ctx, queries, &projectModel)
 
 		SuccessResponse(c, http.StatusOK, projectModel)
 	}
@@ -622,23 +735,15 @@ func GetProjectImage(c *gin.Context) {
 		return
 	}
 
-	// Map to model
-	var blurHash *string
-	if image.BlurHash.Valid {
-		blurHash = &image.BlurHash.String
+	// Get image variants
+	variants, err := queries.ListProjectImageVariantsByImageID(ctx, id)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
 	}
 
-	imageModel := models.ProjectImage{
-		ID:          image.ID,
-		Name:        image.Name,
-		URL:         image.Url,
-		ProjectID:   image.ProjectID,
-		Order:       int(image.Order),
-		BlurHash:    blurHash,
-		Highlighted: image.Highlighted,
-		CreatedAt:   image.CreatedAt.Time,
-		UpdatedAt:   image.UpdatedAt.Time,
-	}
+	// Map to model
+	imageModel := mapSQLCProjectImagesToModels([]sqlc.ProjectImage{image}, variants)[0]
 
 	SuccessResponse(c, http.StatusOK, imageModel)
 }
@@ -680,7 +785,13 @@ func ToggleHighlight(c *gin.Context) {
 		return
 	}
 
-	SuccessResponse(c, http.StatusOK, mapSQLCProjectToModel(project))
+	projectModel := mapSQLCProjectToModel(project)
+	if err := attachProjectCategory(ctx, queries, &projectModel); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, projectModel)
 }
 
 // DeleteProject deletes a project (cascade deletes images via FK)
@@ -707,37 +818,124 @@ func DeleteProject(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// Get project images before deletion (to delete files)
-		images, err := queries.DeleteProjectImagesByProjectID(ctx, id)
+		// Start transaction
+		tx, err := db.Pool.Begin(ctx)
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		qtx := queries.WithTx(tx)
+
+		// Get project images before deletion (to release their files)
+		images, err := qtx.DeleteProjectImagesByProjectID(ctx, id)
 		if err != nil {
 			ErrorResponse(c, http.StatusInternalServerError, "Database error")
 			return
 		}
 
-		// Delete image files from filesystem
+		var releasedFiles []*releasedFile
 		for _, img := range images {
-			storage.DeleteFile(img.Url, cfg.StoragePath)
+			released, err := deleteProjectImage(ctx, qtx, img)
+			if err != nil {
+				ErrorResponse(c, http.StatusInternalServerError, "Failed to delete project image")
+				return
+			}
+			if released != nil {
+				releasedFiles = append(releasedFiles, released)
+			}
 		}
 
-		// Delete project (cascade will delete images from DB, but we already got them)
-		err = queries.DeleteProject(ctx, id)
+		// Delete project
+		err = qtx.DeleteProject(ctx, id)
 		if err != nil {
 			ErrorResponse(c, http.StatusInternalServerError, "Failed to delete project")
 			return
 		}
 
+		// Commit transaction
+		if err := tx.Commit(ctx); err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+			return
+		}
+
+		// Remove files that are no longer referenced
+		deleteStoredFiles(ctx, queries, releasedFiles)
+
 		c.Status(http.StatusNoContent)
 	}
 }
 
 // Helper functions
-func getFormValue(form *multipart.Form, key string) string {
+func getFormValue(form *uploadForm, key string) string {
 	if values, ok := form.Value[key]; ok && len(values) > 0 {
 		return values[0]
 	}
 	return ""
 }
 
+// projectDetails holds the optional descriptive fields of the project form
+type projectDetails struct {
+	Description    pgtype.Text
+	Location       pgtype.Text
+	CompletionYear pgtype.Int4
+	AreaM2         pgtype.Float8
+	DurationMonths pgtype.Int4
+	Services       []string
+}
+
+// getFormProjectDetails reads description, location, completion_year, area_m2, duration_months
+// and services (repeated services[] fields). It responds with 400 and returns false when a
+// number is malformed or out of range.
+func getFormProjectDetails(c *gin.Context, form *uploadForm) (projectDetails, bool) {
+	details := projectDetails{
+		Description: pgtypeTextPtr(strings.TrimSpace(getFormValue(form, "description"))),
+		Location:    pgtypeTextPtr(strings.TrimSpace(getFormValue(form, "location"))),
+		Services:    []string{},
+	}
+
+	if yearStr := getFormValue(form, "completion_year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil || year < 1900 || year > 2100 {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid completion_year", "completion_year must be a year between 1900 and 2100")
+			return details, false
+		}
+		details.CompletionYear = pgtype.Int4{Int32: int32(year), Valid: true}
+	}
+
+	if areaStr := getFormValue(form, "area_m2"); areaStr != "" {
+		area, err := strconv.ParseFloat(areaStr, 64)
+		if err != nil || !(area > 0) || math.IsInf(area, 0) {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid area_m2", "area_m2 must be a positive number")
+			return details, false
+		}
+		details.AreaM2 = pgtype.Float8{Float64: area, Valid: true}
+	}
+
+	if durationStr := getFormValue(form, "duration_months"); durationStr != "" {
+		duration, err := strconv.Atoi(durationStr)
+		if err != nil || duration < 1 {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid duration_months", "duration_months must be a positive integer")
+			return details, false
+		}
+		details.DurationMonths = pgtype.Int4{Int32: int32(duration), Valid: true}
+	}
+
+	seen := make(map[string]bool)
+	for _, key := range []string{"services[]", "services"} {
+		for _, service := range form.Value[key] {
+			service = strings.TrimSpace(service)
+			if service != "" && !seen[service] {
+				seen[service] = true
+				details.Services = append(details.Services, service)
+			}
+		}
+	}
+
+	return details, true
+}
+
 func stringPtr(s string) *string {
 	if s == "" {
 		return nil
@@ -754,9 +952,9 @@ func pgtypeTextPtr(s string) pgtype.Text {
 
 // Helper function to map sqlc Project to models.Project
 func mapSQLCProjectToModel(p sqlc.Project) models.Project {
-	var category *string
-	if p.Category.Valid {
-		category = &p.Category.String
+	var categoryID *int64
+	if p.CategoryID.Valid {
+		categoryID = &p.CategoryID.Int64
 	}
 
 	var client *string
@@ -764,38 +962,303 @@ func mapSQLCProjectToModel(p sqlc.Project) models.Project {
 		client = &p.Client.String
 	}
 
+	var description, location *string
+	if p.Description.Valid {
+		description = &p.Description.String
+	}
+	if p.Location.Valid {
+		location = &p.Location.String
+	}
+
+	var completionYear, durationMonths *int
+	if p.CompletionYear.Valid {
+		year := int(p.CompletionYear.Int32)
+		completionYear = &year
+	}
+	if p.DurationMonths.Valid {
+		months := int(p.DurationMonths.Int32)
+		durationMonths = &months
+	}
+
+	var areaM2 *float64
+	if p.AreaM2.Valid {
+		areaM2 = &p.AreaM2.Float64
+	}
+
+	services := p.Services
+	if services == nil {
+		services = []string{}
+	}
+
 	return models.Project{
-		ID:          p.ID,
-		Status:      int(p.Status),
-		Name:        p.Name,
-		Category:    category,
-		Client:      client,
-		Order:       int(p.Order),
-		Highlighted: p.Highlighted,
-		CreatedAt:   p.CreatedAt.Time,
-		UpdatedAt:   p.UpdatedAt.Time,
+		ID:             p.ID,
+		Status:         p.Status,
+		Name:           p.Name,
+		Slug:           p.Slug,
+		CategoryID:     categoryID,
+		Client:         client,
+		Order:          int(p.Order),
+		Highlighted:    p.Highlighted,
+		Description:    description,
+		Location:       location,
+		CompletionYear: completionYear,
+		AreaM2:         areaM2,
+		DurationMonths: durationMonths,
+		Services:       services,
+		PublishAt:      timestamptzToPtr(p.PublishAt),
+		UnpublishAt:    timestamptzToPtr(p.UnpublishAt),
+		CreatedAt:      p.CreatedAt.Time,
+		UpdatedAt:      p.UpdatedAt.Time,
+	}
+}
+
+// loadProjectImages loads a project's images together with their variants
+func loadProjectImages(ctx context.Context, queries *sqlc.Queries, projectID int64) ([]models.ProjectImage, error) {
+	images, err := queries.ListProjectImagesByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := queries.ListProjectImageVariantsByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapSQLCProjectImagesToModels(images, variants), nil
+}
+
+// loadProjectModels maps projects to models together with their images (and image variants)
+// and categories. Each relation is loaded for all projects in a single query, so listings
+// cost the same number of round trips however many projects they contain
+func loadProjectModels(ctx context.Context, queries *sqlc.Queries, projects []sqlc.Project) ([]models.Project, error) {
+	projectModels := make([]models.Project, len(projects))
+	projectIDs := make([]int64, len(projects))
+	for i, p := range projects {
+		projectModels[i] = mapSQLCProjectToModel(p)
+		projectIDs[i] = p.ID
+	}
+	if len(projects) == 0 {
+		return projectModels, nil
+	}
+
+	images, err := queries.ListProjectImagesByProjectIDs(ctx, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("list project images: %w", err)
+	}
+
+	variants, err := queries.ListProjectImageVariantsByProjectIDs(ctx, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("list project image variants: %w", err)
+	}
+
+	imagesByProject := make(map[int64][]models.ProjectImage, len(projects))
+	for _, img := range mapSQLCProjectImagesToModels(images, variants) {
+		imagesByProject[img.ProjectID] = append(imagesByProject[img.ProjectID], img)
+	}
+	for i := range projectModels {
+		projectModels[i].Images = imagesByProject[projectModels[i].ID]
+	}
+
+	if err := attachProjectCategories(ctx, queries, projectModels); err != nil {
+		return nil, fmt.Errorf("list project categories: %w", err)
+	}
+	return projectModels, nil
+}
+
+// releasedFile is a stored original (and its variant copies) whose last reference was deleted
+type releasedFile struct {
+	URL      string
+	Variants []string
+}
+
+// deleteProjectImage deletes an image row and releases its reference to the stored file.
+// It returns the file if no other image references it any more; it must only be removed
+// from storage (with deleteStoredFiles) after the transaction commits
+func deleteProjectImage(ctx context.Context, qtx *sqlc.Queries, img sqlc.ProjectImage) (*releasedFile, error) {
+	variants, err := qtx.ListProjectImageVariantsByImageID(ctx, img.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete from database (variants cascade)
+	if err := qtx.DeleteProjectImage(ctx, img.ID); err != nil {
+		return nil, err
+	}
+
+	remaining, err := qtx.ReleaseStoredBlob(ctx, img.Url)
+	if err != nil {
+		// Untracked file: leave it alone, the storage GC decides what to do with it
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
 	}
+	if remaining > 0 {
+		return nil, nil
+	}
+
+	if _, err := qtx.DeleteUnreferencedStoredBlob(ctx, img.Url); err != nil {
+		return nil, err
+	}
+
+	released := &releasedFile{URL: img.Url}
+	for _, v := range variants {
+		released.Variants = append(released.Variants, v.Url)
+	}
+	return released, nil
 }
 
-// Helper function to map sqlc ProjectImages to models.ProjectImage
-func mapSQLCProjectImagesToModels(images []sqlc.ProjectImage) []models.ProjectImage {
+// reuploadWindow covers identical uploads that stored a file but have not committed its image row yet
+const reuploadWindow = 10 * time.Minute
+
+// deleteStoredFiles removes released files from storage once their transaction has committed.
+// Files are content-addressed, so an identical upload may have re-acquired one in the meantime:
+// files referenced again, or written within reuploadWindow, are kept (and left for the storage GC,
+// like any failure)
+func deleteStoredFiles(ctx context.Context, queries *sqlc.Queries, files []*releasedFile) {
+	cutoff := time.Now().Add(-reuploadWindow)
+	for _, file := range files {
+		inUse, err := queries.StoredFileInUse(ctx, file.URL)
+		if err != nil || inUse {
+			continue
+		}
+
+		key, err := storage.KeyFromURL(file.URL)
+		if err != nil {
+			continue
+		}
+		if info, err := storage.Store.Stat(ctx, key); err == nil && info.ModTime.After(cutoff) {
+			continue
+		}
+
+		for _, url := range file.Variants {
+			storage.DeleteFile(ctx, url)
+		}
+		storage.DeleteFile(ctx, file.URL)
+	}
+}
+
+// captureMetadataParams converts extracted EXIF metadata into column values.
+// Nothing is stored unless metadata extraction is enabled in config
+func captureMetadataParams(meta *storage.ImageMetadata, cfg *config.Config) (pgtype.Timestamp, pgtype.Float8, pgtype.Float8) {
+	var capturedAt pgtype.Timestamp
+	var latitude, longitude pgtype.Float8
+	if !cfg.Images.ExtractMetadata || meta == nil {
+		return capturedAt, latitude, longitude
+	}
+
+	if meta.CapturedAt != nil {
+		capturedAt = pgtype.Timestamp{Time: *meta.CapturedAt, Valid: true}
+	}
+	if meta.Latitude != nil && meta.Longitude != nil {
+		latitude = pgtype.Float8{Float64: *meta.Latitude, Valid: true}
+		longitude = pgtype.Float8{Float64: *meta.Longitude, Valid: true}
+	}
+	return capturedAt, latitude, longitude
+}
+
+// Helper function to map sqlc ProjectImages (and their variants) to models.ProjectImage
+func mapSQLCProjectImagesToModels(images []sqlc.ProjectImage, variants []sqlc.ProjectImageVariant) []models.ProjectImage {
+	variantsByImage := make(map[int64][]models.ImageVariant)
+	for _, v := range variants {
+		variantsByImage[v.ProjectImageID] = append(variantsByImage[v.ProjectImageID], models.ImageVariant{
+			Width:  int(v.Width),
+			Height: int(v.Height),
+			Format: v.Format,
+			URL:    v.Url,
+		})
+	}
+
 	result := make([]models.ProjectImage, len(images))
 	for i, img := range images {
-		var blurHash *string
+		var blurHash, placeholder *string
 		if img.BlurHash.Valid {
-			blurHash = &img.BlurHash.String
+			hash := storage.NormalizeBlurHash(img.BlurHash.String)
+			blurHash = &hash
+			placeholderURL := fmt.Sprintf("/storage/placeholder/%d", img.ID)
+			placeholder = &placeholderURL
+		}
+
+		imageVariants := variantsByImage[img.ID]
+		var srcSet, webpSrcSet []string
+		for _, v := range imageVariants {
+			entry := fmt.Sprintf("%s %dw", v.URL, v.Width)
+			if v.Format == storage.FormatWebP {
+				webpSrcSet = append(webpSrcSet, entry)
+			} else {
+				srcSet = append(srcSet, entry)
+			}
+		}
+
+		var width, height *int
+		if img.Width.Valid && img.Height.Valid {
+			w, h := int(img.Width.Int32), int(img.Height.Int32)
+			width, height = &w, &h
+		}
+
+		var sizeBytes *int64
+		if img.SizeBytes.Valid {
+			sizeBytes = &img.SizeBytes.Int64
+		}
+
+		var mimeType, dominantColor *string
+		if img.MimeType.Valid {
+			mimeType = &img.MimeType.String
+		}
+		if img.DominantColor.Valid {
+			dominantColor = &img.DominantColor.String
+		}
+
+		var altText, caption *string
+		if img.AltText.Valid {
+			altText = &img.AltText.String
+		}
+		if img.Caption.Valid {
+			caption = &img.Caption.String
+		}
+
+		var processingError *string
+		if img.ProcessingError.Valid {
+			processingError = &img.ProcessingError.String
+		}
+
+		var capturedAt *time.Time
+		if img.CapturedAt.Valid {
+			capturedAt = &img.CapturedAt.Time
+		}
+
+		var gpsLatitude, gpsLongitude *float64
+		if img.GpsLatitude.Valid && img.GpsLongitude.Valid {
+			gpsLatitude = &img.GpsLatitude.Float64
+			gpsLongitude = &img.GpsLongitude.Float64
 		}
 
 		result[i] = models.ProjectImage{
-			ID:          img.ID,
-			Name:        img.Name,
-			URL:         img.Url,
-			ProjectID:   img.ProjectID,
-			Order:       int(img.Order),
-			BlurHash:    blurHash,
-			Highlighted: img.Highlighted,
-			CreatedAt:   img.CreatedAt.Time,
-			UpdatedAt:   img.UpdatedAt.Time,
+			ID:               img.ID,
+			Name:             img.Name,
+			URL:              img.Url,
+			ProjectID:        img.ProjectID,
+			Order:            int(img.Order),
+			BlurHash:         blurHash,
+			Placeholder:      placeholder,
+			Highlighted:      img.Highlighted,
+			AltText:          altText,
+			Caption:          caption,
+			ProcessingStatus: img.ProcessingStatus,
+			ProcessingError:  processingError,
+			Width:            width,
+			Height:           height,
+			SizeBytes:        sizeBytes,
+			MimeType:         mimeType,
+			DominantColor:    dominantColor,
+			Variants:         imageVariants,
+			SrcSet:           strings.Join(srcSet, ", "),
+			WebPSrcSet:       strings.Join(webpSrcSet, ", "),
+			CapturedAt:       capturedAt,
+			GPSLatitude:      gpsLatitude,
+			GPSLongitude:     gpsLongitude,
+			CreatedAt:        img.CreatedAt.Time,
+			UpdatedAt:        img.UpdatedAt.Time,
 		}
 	}
 	return result
@@ -1,47 +1,61 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/models"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/slug"
 	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 )
 
+// maxPublicPerPage caps ?per_page= of the paginated public project listing
+const maxPublicPerPage = 50
+
+// publicProjectSortFields are the allowed ?sort_by= values of the public project listings
+var publicProjectSortFields = []string{"order", "name", "created_at", "completion_year"}
+
 // Ping returns a welcome message
 func Ping(c *gin.Context) {
 	SuccessResponse(c, http.StatusOK, gin.H{"message": "Welcome to API 1.0"})
 }
 
-// GetPublicProjects returns all projects (no pagination)
+// GetPublicProjects returns all published projects (no pagination)
+// Supports the filters and sorting of parsePublicProjectFilters
 func GetPublicProjects(c *gin.Context) {
+	filters, ok := parsePublicProjectFilters(c)
+	if !ok {
+		return
+	}
+
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
 
-	projects, err := queries.ListPublicProjects(ctx)
+	projects, err := queries.ListPublicProjects(ctx, filters)
 	if err != nil {
 		ErrorResponse(c, http.StatusInternalServerError, "Database error")
 		return
 	}
 
-	// Map and load images
-	projectModels := make([]models.Project, len(projects))
-	for i, p := range projects {
-		projectModels[i] = mapSQLCProjectToModel(p)
-		images, err := queries.ListProjectImagesByProjectID(ctx, p.ID)
-		if err == nil {
-			projectModels[i].Images = mapSQLCProjectImagesToModels(images)
-		}
+	// Map and load images, categories and search snippets
+	projectModels, err := loadPublicProjectModels(ctx, queries, projects, filters.Column1)
+	if err != nil {
+		c.Error(err)
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
 	}
 
 	SuccessResponse(c, http.StatusOK, gin.H{"data": projectModels})
 }
 
-// GetHighlightedProjects returns only highlighted projects
+// GetHighlightedProjects returns only highlighted published projects
 func GetHighlightedProjects(c *gin.Context) {
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
@@ -52,55 +66,126 @@ func GetHighlightedProjects(c *gin.Context) {
 		return
 	}
 
-	// Map and load images
-	projectModels := make([]models.Project, len(projects))
-	for i, p := range projects {
-		projectModels[i] = mapSQLCProjectToModel(p)
-		images, err := queries.ListProjectImagesByProjectID(ctx, p.ID)
-		if err == nil {
-			projectModels[i].Images = mapSQLCProjectImagesToModels(images)
-		}
+	// Map and load images and categories
+	projectModels, err := loadPublicProjectModels(ctx, queries, projects, "")
+	if err != nil {
+		c.Error(err)
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
 	}
 
 	SuccessResponse(c, http.StatusOK, gin.H{"data": projectModels})
 }
 
-// GetPublicProjectsPaginated returns paginated projects (3 per page)
+// GetPublicProjectsPaginated returns paginated published projects (3 per page, ?per_page= up to 50)
+// Supports the filters and sorting of parsePublicProjectFilters. For infinite scroll,
+// ?cursor=&limit= paginates by cursor instead, so no project is shown twice when the order changes
 func GetPublicProjectsPaginated(c *gin.Context) {
+	respondWithPublicProjectsPage(c, 3)
+}
+
+// SearchPublicProjects searches published projects (?q=, required), best matches first, each with
+// a highlighted snippet. Paginated like GetPublicProjectsPaginated (10 per page) with the same filters
+func SearchPublicProjects(c *gin.Context) {
+	if searchQuery(ParseQueryParams(c).Search) == "" {
+		ErrorResponse(c, http.StatusBadRequest, "Search query is required", "q must contain letters or digits")
+		return
+	}
+	respondWithPublicProjectsPage(c, 10)
+}
+
+// respondWithPublicProjectsPage writes a page of published projects, in page or cursor mode
+func respondWithPublicProjectsPage(c *gin.Context, defaultPerPage int) {
 	pageStr := c.DefaultQuery("page", "1")
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
 		page = 1
 	}
 
+	filters, ok := parsePublicProjectFilters(c)
+	if !ok {
+		return
+	}
+
+	keysetSort := filters.Column6
+	if keysetSort == "" {
+		keysetSort = "order"
+	}
+	cursorReq, cursorMode, ok := parseCursorRequest(c, keysetSort+":"+filters.Column7, defaultPerPage, maxPublicPerPage)
+	if !ok {
+		return
+	}
+
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
-	perPage := 3
+
+	if cursorMode {
+		// Keyset pagination keeps infinite scroll free of duplicates while projects are reordered or published
+		projects, err := queries.ListPublicProjectsKeyset(ctx, sqlc.ListPublicProjectsKeysetParams{
+			Column1:  filters.Column1,
+			Column2:  filters.Column2,
+			Column3:  filters.Column3,
+			Column4:  filters.Column4,
+			Column5:  filters.Column5,
+			Column6:  keysetSort,
+			Column7:  cursorReq.fetchDescending(filters.Column7 == "desc"),
+			Column8:  cursorReq.afterInt(),
+			Column9:  cursorReq.afterText(),
+			Column10: cursorReq.afterTime(),
+			Column11: cursorReq.afterID(),
+			Limit:    int32(cursorReq.Limit + 1),
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		projects, next, prev := paginateCursor(cursorReq, projects, projectCursor(keysetSort))
+		projectModels, err := loadPublicProjectModels(ctx, queries, projects, filters.Column1)
+		if err != nil {
+			c.Error(err)
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		SuccessResponse(c, http.StatusOK, models.CursorPaginationResponse{
+			Data:       projectModels,
+			Limit:      cursorReq.Limit,
+			NextCursor: next,
+			PrevCursor: prev,
+		})
+		return
+	}
+
+	perPage := ParsePerPage(c, defaultPerPage, maxPublicPerPage)
 	offset := (page - 1) * perPage
 
-	projects, err := queries.ListPublicProjectsPaginated(ctx, sqlc.ListPublicProjectsPaginatedParams{
-		Limit:  int32(perPage),
-		Offset: int32(offset),
-	})
+	filters.Column8 = int32(perPage)
+	filters.Column9 = int32(offset)
+	projects, err := queries.ListPublicProjects(ctx, filters)
 	if err != nil {
 		ErrorResponse(c, http.StatusInternalServerError, "Database error")
 		return
 	}
 
-	total, err := queries.CountProjects(ctx)
+	total, err := queries.CountPublishedProjects(ctx, sqlc.CountPublishedProjectsParams{
+		Column1: filters.Column1,
+		Column2: filters.Column2,
+		Column3: filters.Column3,
+		Column4: filters.Column4,
+		Column5: filters.Column5,
+	})
 	if err != nil {
 		ErrorResponse(c, http.StatusInternalServerError, "Database error")
 		return
 	}
 
-	// Map and load images
-	projectModels := make([]models.Project, len(projects))
-	for i, p := range projects {
-		projectModels[i] = mapSQLCProjectToModel(p)
-		images, err := queries.ListProjectImagesByProjectID(ctx, p.ID)
-		if err == nil {
-			projectModels[i].Images = mapSQLCProjectImagesToModels(images)
-		}
+	// Map and load images, categories and search snippets
+	projectModels, err := loadPublicProjectModels(ctx, queries, projects, filters.Column1)
+	if err != nil {
+		c.Error(err)
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
 	}
 
 	SuccessResponse(c, http.StatusOK, models.PaginationResponse{
@@ -111,7 +196,7 @@ func GetPublicProjectsPaginated(c *gin.Context) {
 	})
 }
 
-// GetPublicProject returns a single project with images
+// GetPublicProject returns a single published project with images
 func GetPublicProject(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -123,7 +208,7 @@ func GetPublicProject(c *gin.Context) {
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
 
-	project, err := queries.GetProjectByID(ctx, id)
+	project, err := queries.GetPublishedProjectByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			ErrorResponse(c, http.StatusNotFound, "Project not found")
@@ -133,14 +218,18 @@ func GetPublicProject(c *gin.Context) {
 		return
 	}
 
-	images, err := queries.ListProjectImagesByProjectID(ctx, id)
+	images, err := loadProjectImages(ctx, queries, id)
 	if err != nil {
 		ErrorResponse(c, http.StatusInternalServerError, "Database error")
 		return
 	}
 
 	projectModel := mapSQLCProjectToModel(project)
-	projectModel.Images = mapSQLCProjectImagesToModels(images)
+	projectModel.Images = hideInternalImageData(images)
+	if err := attachProjectCategory(ctx, queries, &projectModel); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
 
 	SuccessResponse(c, http.StatusOK, projectModel)
 }
@@ -255,6 +344,78 @@ func CreateVisitorMessage(c *gin.Context) {
 	SuccessResponse(c, http.StatusCreated, mapSQLCVisitorMessageToModel(created))
 }
 
+// parsePublicProjectFilters reads the filters and sorting of the public project listings:
+// ?q=term&category=slug&client=name&year=2023&highlighted=true|false&sort_by=field&sort_order=asc|desc
+// It responds with 400 and returns false when year or highlighted is malformed, or when q or
+// category has no letters or digits.
+func parsePublicProjectFilters(c *gin.Context) (sqlc.ListPublicProjectsParams, bool) {
+	params := ParseQueryParams(c)
+	filters := sqlc.ListPublicProjectsParams{
+		Column1: searchQuery(params.Search),
+		Column2: slug.Make(c.Query("category")),
+		Column3: strings.TrimSpace(c.Query("client")),
+		Column6: ValidateSortBy(params.SortBy, publicProjectSortFields),
+		Column7: ValidateSortOrder(params.SortOrder),
+	}
+
+	// A filter that normalizes to nothing would silently match every project
+	if params.Search != "" && filters.Column1 == "" {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid search query", "q must contain letters or digits")
+		return filters, false
+	}
+	if strings.TrimSpace(c.Query("category")) != "" && filters.Column2 == "" {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid category", "category must be a category slug")
+		return filters, false
+	}
+
+	if yearStr := c.Query("year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil || year < 1 {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid year")
+			return filters, false
+		}
+		filters.Column4 = int32(year)
+	}
+
+	if highlightedStr := c.Query("highlighted"); highlightedStr != "" {
+		highlighted, err := strconv.ParseBool(highlightedStr)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid highlighted", "highlighted must be true or false")
+			return filters, false
+		}
+		filters.Column5 = strconv.FormatBool(highlighted)
+	}
+
+	return filters, true
+}
+
+// loadPublicProjectModels maps published projects with their images, categories and, when the
+// list was searched with tsquery, snippets, leaving out image data that is not public
+func loadPublicProjectModels(ctx context.Context, queries *sqlc.Queries, projects []sqlc.Project, tsquery string) ([]models.Project, error) {
+	projectModels, err := loadProjectModels(ctx, queries, projects)
+	if err != nil {
+		return nil, err
+	}
+	for i := range projectModels {
+		projectModels[i].Images = hideInternalImageData(projectModels[i].Images)
+	}
+	if err := attachProjectSnippets(ctx, queries, tsquery, projectModels); err != nil {
+		return nil, fmt.Errorf("list project search snippets: %w", err)
+	}
+	return projectModels, nil
+}
+
+// hideInternalImageData removes capture metadata (date, GPS position) that must never be exposed publicly
+func hideInternalImageData(images []models.ProjectImage) []models.ProjectImage {
+	for i := range images {
+		images[i].CapturedAt = nil
+		images[i].GPSLatitude = nil
+		images[i].GPSLongitude = nil
+		images[i].ProcessingError = nil
+	}
+	return images
+}
+
 // Helper functions for mapping sqlc types to models
 func mapSQLCTestimonialToModel(t sqlc.Testimonial) models.Testimonial {
 	return models.Testimonial{
@@ -263,6 +424,8 @@ func mapSQLCTestimonialToModel(t sqlc.Testimonial) models.Testimonial {
 		Profession:  t.Profession,
 		Testimonial: t.Testimonial,
 		Status:      t.Status,
+		PublishAt:   timestamptzToPtr(t.PublishAt),
+		UnpublishAt: timestamptzToPtr(t.UnpublishAt),
 		CreatedAt:   t.CreatedAt.Time,
 		UpdatedAt:   t.UpdatedAt.Time,
 	}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParsePublicProjectFilters(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		wantOK       bool
+		wantSearch   string
+		wantCategory string
+	}{
+		{name: "no filters", query: "", wantOK: true},
+		{name: "search", query: "q=Novi+Sad", wantOK: true, wantSearch: "novi:* & sad:*"},
+		{name: "blank search", query: "q=+++", wantOK: true},
+		{name: "punctuation only search", query: "q=---", wantOK: false},
+		{name: "category", query: "category=Stambeni+objekti", wantOK: true, wantCategory: "stambeni-objekti"},
+		{name: "blank category", query: "category=+", wantOK: true},
+		{name: "punctuation only category", query: "category=%21%21", wantOK: false},
+		{name: "invalid year", query: "year=abc", wantOK: false},
+		{name: "invalid highlighted", query: "highlighted=maybe", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := testContext(tt.query)
+			filters, ok := parsePublicProjectFilters(c)
+			if ok != tt.wantOK {
+				t.Fatalf("parsePublicProjectFilters() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				if w.Code != http.StatusBadRequest {
+					t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+				}
+				return
+			}
+			if filters.Column1 != tt.wantSearch {
+				t.Errorf("search = %q, want %q", filters.Column1, tt.wantSearch)
+			}
+			if filters.Column2 != tt.wantCategory {
+				t.Errorf("category = %q, want %q", filters.Column2, tt.wantCategory)
+			}
+		})
+	}
+}
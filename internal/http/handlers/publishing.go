@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/models"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetPublicationEvents returns the publishing scheduler's audit trail (?page=&per_page=, 20 per page by default)
+// Supports query parameters: ?entity_type=project|testimonial&entity_id=42
+// With ?cursor= or ?limit= the list is paginated by cursor instead (see parseCursorRequest)
+func GetPublicationEvents(c *gin.Context) {
+	pageStr := c.DefaultQuery("page", "1")
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	entityType := c.Query("entity_type")
+	if entityType != "" && entityType != "project" && entityType != "testimonial" {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid entity_type", "entity_type must be project or testimonial")
+		return
+	}
+
+	var entityID int64
+	if entityIDStr := c.Query("entity_id"); entityIDStr != "" {
+		entityID, err = strconv.ParseInt(entityIDStr, 10, 64)
+		if err != nil || entityID < 1 {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid entity_id")
+			return
+		}
+	}
+
+	cursorReq, cursorMode, ok := parseCursorRequest(c, "created_at:desc", 20, maxPageLimit)
+	if !ok {
+		return
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	if cursorMode {
+		events, err := queries.ListPublicationEventsKeyset(ctx, sqlc.ListPublicationEventsKeysetParams{
+			Column1: entityType,
+			Column2: entityID,
+			Column3: cursorReq.before(),
+			Column4: cursorReq.afterTime(),
+			Column5: cursorReq.afterID(),
+			Limit:   int32(cursorReq.Limit + 1),
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		events, next, prev := paginateCursor(cursorReq, events, func(e sqlc.PublicationEvent) pageCursor {
+			return timeCursor(e.ID, e.CreatedAt)
+		})
+		SuccessResponse(c, http.StatusOK, models.CursorPaginationResponse{
+			Data:       mapSQLCPublicationEventsToModels(events),
+			Limit:      cursorReq.Limit,
+			NextCursor: next,
+			PrevCursor: prev,
+		})
+		return
+	}
+
+	perPage := ParsePerPage(c, 20, maxPageLimit)
+	offset := (page - 1) * perPage
+
+	events, err := queries.ListPublicationEvents(ctx, sqlc.ListPublicationEventsParams{
+		Column1: entityType,
+		Column2: entityID,
+		Limit:   int32(perPage),
+		Offset:  int32(offset),
+	})
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	total, err := queries.CountPublicationEvents(ctx, sqlc.CountPublicationEventsParams{
+		Column1: entityType,
+		Column2: entityID,
+	})
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, models.PaginationResponse{
+		Data:    mapSQLCPublicationEventsToModels(events),
+		Page:    page,
+		PerPage: perPage,
+		Total:   total,
+	})
+}
+
+func mapSQLCPublicationEventsToModels(events []sqlc.PublicationEvent) []models.PublicationEvent {
+	eventModels := make([]models.PublicationEvent, len(events))
+	for i, e := range events {
+		eventModels[i] = models.PublicationEvent{
+			ID:          e.ID,
+			EntityType:  e.EntityType,
+			EntityID:    e.EntityID,
+			Action:      e.Action,
+			FromStatus:  e.FromStatus,
+			ToStatus:    e.ToStatus,
+			ScheduledAt: e.ScheduledAt.Time,
+			CreatedAt:   e.CreatedAt.Time,
+		}
+	}
+	return eventModels
+}
+
+// getFormSchedule reads the optional publish_at/unpublish_at form fields (RFC 3339).
+// It responds with 400 and returns false when a value is malformed or the window is empty.
+func getFormSchedule(c *gin.Context, form *uploadForm) (publishAt, unpublishAt *time.Time, ok bool) {
+	for _, field := range []struct {
+		name string
+		dst  **time.Time
+	}{{"publish_at", &publishAt}, {"unpublish_at", &unpublishAt}} {
+		value := getFormValue(form, field.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid "+field.name, field.name+" must be an RFC 3339 timestamp (e.g. 2025-03-01T09:00:00+01:00)")
+			return nil, nil, false
+		}
+		*field.dst = &t
+	}
+
+	if err := validateSchedule(publishAt, unpublishAt); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid schedule", err.Error())
+		return nil, nil, false
+	}
+	return publishAt, unpublishAt, true
+}
+
+// validateSchedule checks that a publishing window is not empty
+func validateSchedule(publishAt, unpublishAt *time.Time) error {
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return fmt.Errorf("unpublish_at must be after publish_at")
+	}
+	return nil
+}
+
+func pgtypeTimestamptzPtr(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{Valid: false}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func timestamptzToPtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
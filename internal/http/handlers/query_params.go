@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -14,10 +15,16 @@ type QueryParams struct {
 }
 
 // ParseQueryParams extracts common query parameters from gin.Context
-// This can be reused across different endpoints
+// This can be reused across different endpoints. The search term is read from
+// ?search= or its short form ?q=
 func ParseQueryParams(c *gin.Context) QueryParams {
+	search := c.Query("search")
+	if search == "" {
+		search = c.Query("q")
+	}
+
 	return QueryParams{
-		Search:    strings.TrimSpace(c.DefaultQuery("search", "")),
+		Search:    strings.TrimSpace(search),
 		SortBy:    strings.TrimSpace(c.DefaultQuery("sort_by", "")),
 		SortOrder: strings.ToLower(strings.TrimSpace(c.DefaultQuery("sort_order", "asc"))),
 	}
@@ -44,3 +51,16 @@ func ValidateSortBy(sortBy string, allowedFields []string) string {
 	}
 	return ""
 }
+
+// ParsePerPage reads the per_page query parameter
+// Returns defaultPerPage if missing or invalid, and at most maxPerPage
+func ParsePerPage(c *gin.Context, defaultPerPage, maxPerPage int) int {
+	perPage, err := strconv.Atoi(c.Query("per_page"))
+	if err != nil || perPage < 1 {
+		return defaultPerPage
+	}
+	if perPage > maxPerPage {
+		return maxPerPage
+	}
+	return perPage
+}
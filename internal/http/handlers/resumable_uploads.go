@@ -0,0 +1,447 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/jobs"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/models"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Upload states stored in uploads.status
+const (
+	uploadUploading = "uploading"
+	uploadComplete  = "complete"
+)
+
+// uploadOffsetHeader carries the offset of a chunk (request) and the bytes received so far (response)
+const uploadOffsetHeader = "Upload-Offset"
+
+// CreateUploadRequest is the body of POST /api/uploads
+type CreateUploadRequest struct {
+	Filename string `json:"filename" binding:"required"`
+	Size     int64  `json:"size" binding:"required,gt=0"`
+}
+
+// FinalizeUploadRequest is the (optional) body of POST /api/uploads/:id/finalize
+type FinalizeUploadRequest struct {
+	SHA1 string `json:"sha1"`
+}
+
+// AttachUploadsRequest is the body of POST /api/projects/:id/images/uploads
+type AttachUploadsRequest struct {
+	UploadIDs []string `json:"upload_ids" binding:"required,min=1"`
+}
+
+// CreateUpload starts a resumable upload
+func CreateUpload(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateUploadRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+			return
+		}
+
+		if req.Size > cfg.Uploads.MaxFileSize {
+			ErrorResponse(c, http.StatusRequestEntityTooLarge, "File too large",
+				fmt.Sprintf("%s exceeds the maximum file size of %d bytes", req.Filename, cfg.Uploads.MaxFileSize))
+			return
+		}
+
+		id, err := storage.NewUploadID()
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to create upload")
+			return
+		}
+
+		queries := sqlc.New(db.Pool)
+		ctx := c.Request.Context()
+
+		upload, err := queries.CreateUpload(ctx, sqlc.CreateUploadParams{
+			ID:       id,
+			Filename: req.Filename,
+			Size:     req.Size,
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to create upload")
+			return
+		}
+
+		c.Header(uploadOffsetHeader, "0")
+		SuccessResponse(c, http.StatusCreated, mapSQLCUploadToModel(upload))
+	}
+}
+
+// GetUpload returns the state of an upload, including the offset to resume from
+func GetUpload(c *gin.Context) {
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	upload, err := queries.GetUpload(ctx, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusNotFound, "Upload not found")
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	c.Header(uploadOffsetHeader, strconv.FormatInt(upload.Received, 10))
+	SuccessResponse(c, http.StatusOK, mapSQLCUploadToModel(upload))
+}
+
+// AppendUploadChunk appends the raw request body to an upload. The Upload-Offset header must match
+// the bytes received so far; whatever arrives of an interrupted chunk is kept, so the client can
+// resume from the offset returned by GetUpload. The body is streamed without holding a database
+// connection: chunks of one upload are serialized by the chunk store, and progress is recorded
+// with a compare-and-swap on the offset
+func AppendUploadChunk(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		offset, err := strconv.ParseInt(c.GetHeader(uploadOffsetHeader), 10, 64)
+		if err != nil || offset < 0 {
+			ErrorResponse(c, http.StatusBadRequest, "Upload-Offset header is required")
+			return
+		}
+
+		queries := sqlc.New(db.Pool)
+		// Keep recording progress even if the client disconnects mid-chunk
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		// Only one chunk of an upload is written at a time
+		unlock, ok := storage.Chunks.TryLock(id)
+		if !ok {
+			ErrorResponse(c, http.StatusConflict, "Upload is busy", "another chunk of this upload is still being written")
+			return
+		}
+		defer unlock()
+
+		upload, err := queries.GetUpload(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				ErrorResponse(c, http.StatusNotFound, "Upload not found")
+				return
+			}
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		c.Header(uploadOffsetHeader, strconv.FormatInt(upload.Received, 10))
+		if upload.Status != uploadUploading {
+			ErrorResponse(c, http.StatusConflict, "Upload is already finalized")
+			return
+		}
+		if offset != upload.Received {
+			ErrorResponse(c, http.StatusConflict, "Upload offset mismatch", fmt.Sprintf("expected offset %d", upload.Received))
+			return
+		}
+
+		remaining := min(upload.Size-upload.Received, cfg.Uploads.MaxRequestSize)
+		if c.Request.ContentLength > remaining {
+			ErrorResponse(c, http.StatusRequestEntityTooLarge, "Chunk too large",
+				fmt.Sprintf("%s: chunk of %d bytes exceeds the %d bytes allowed", upload.Filename, c.Request.ContentLength, remaining))
+			return
+		}
+
+		body := http.MaxBytesReader(c.Writer, c.Request.Body, remaining)
+		written, copyErr := storage.Chunks.Append(id, offset, body)
+		if errors.Is(copyErr, storage.ErrChunkDataMissing) {
+			ErrorResponse(c, http.StatusConflict, "Upload data missing", "the data received so far is not available on this server, start a new upload")
+			return
+		}
+
+		// Record whatever arrived, even if the chunk was cut short, unless the upload moved on meanwhile
+		received := offset + written
+		updated, err := queries.AdvanceUploadReceived(ctx, sqlc.AdvanceUploadReceivedParams{
+			ID:         id,
+			Received:   offset,
+			Received_2: received,
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to update upload")
+			return
+		}
+		if updated == 0 {
+			ErrorResponse(c, http.StatusConflict, "Upload offset mismatch", "the upload changed while the chunk was written")
+			return
+		}
+
+		c.Header(uploadOffsetHeader, strconv.FormatInt(received, 10))
+		if copyErr != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(copyErr, &maxBytesErr) {
+				ErrorResponse(c, http.StatusRequestEntityTooLarge, "Chunk too large",
+					fmt.Sprintf("%s: chunk exceeds the %d bytes allowed", upload.Filename, remaining))
+				return
+			}
+			ErrorResponse(c, http.StatusBadRequest, "Failed to read chunk", copyErr.Error())
+			return
+		}
+
+		upload.Received = received
+		SuccessResponse(c, http.StatusOK, mapSQLCUploadToModel(upload))
+	}
+}
+
+// FinalizeUpload checks that an upload is complete and a valid image (optionally verifying
+// a client-supplied SHA1), after which it can be attached to a project
+func FinalizeUpload(c *gin.Context) {
+	var req FinalizeUploadRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	id := c.Param("id")
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	// Start transaction
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := queries.WithTx(tx)
+
+	upload, err := qtx.GetUploadForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusNotFound, "Upload not found")
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	// Finalizing twice is harmless
+	if upload.Status == uploadComplete {
+		SuccessResponse(c, http.StatusOK, mapSQLCUploadToModel(upload))
+		return
+	}
+
+	if upload.Received != upload.Size {
+		ErrorResponse(c, http.StatusConflict, "Upload is incomplete", fmt.Sprintf("received %d of %d bytes", upload.Received, upload.Size))
+		return
+	}
+
+	hash, contentType, err := storage.Chunks.Inspect(id)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to read upload")
+		return
+	}
+	if !storage.IsAllowedImageType(contentType) {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid image type", fmt.Sprintf("%s: %s", upload.Filename, contentType))
+		return
+	}
+	if req.SHA1 != "" && !strings.EqualFold(req.SHA1, hash) {
+		ErrorResponse(c, http.StatusBadRequest, "Checksum mismatch", fmt.Sprintf("expected %s, got %s", req.SHA1, hash))
+		return
+	}
+
+	upload, err = qtx.CompleteUpload(ctx, sqlc.CompleteUploadParams{
+		ID:   id,
+		Sha1: pgtype.Text{String: hash, Valid: true},
+	})
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to finalize upload")
+		return
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, mapSQLCUploadToModel(upload))
+}
+
+// DeleteUpload aborts an upload and removes its data
+func DeleteUpload(c *gin.Context) {
+	id := c.Param("id")
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	if _, err := queries.GetUpload(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusNotFound, "Upload not found")
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	if err := queries.DeleteUpload(ctx, id); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to delete upload")
+		return
+	}
+	if err := storage.Chunks.Remove(id); err != nil {
+		log.Printf("Failed to remove upload file %s: %v", id, err)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AttachUploadedImages adds finalized uploads to a project as new images (appended after existing ones)
+func AttachUploadedImages(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, ok := parseIDParam(c, "id", "Invalid project ID")
+		if !ok {
+			return
+		}
+
+		var req AttachUploadsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid request body", err.Error())
+			return
+		}
+		if len(req.UploadIDs) > cfg.Uploads.MaxFiles {
+			ErrorResponse(c, http.StatusRequestEntityTooLarge, "Too many files",
+				fmt.Sprintf("at most %d files can be attached per request", cfg.Uploads.MaxFiles))
+			return
+		}
+
+		queries := sqlc.New(db.Pool)
+		ctx := c.Request.Context()
+
+		if !ensureProjectExists(c, queries, projectID) {
+			return
+		}
+
+		// Store the finished uploads before the transaction
+		stored := make([]*storedImage, len(req.UploadIDs))
+		for i, uploadID := range req.UploadIDs {
+			upload, ok := getCompletedUpload(c, queries, uploadID)
+			if !ok {
+				return
+			}
+
+			path, err := storage.Chunks.Path(uploadID)
+			if err != nil {
+				ErrorResponse(c, http.StatusNotFound, "Upload not found", uploadID)
+				return
+			}
+			file := &uploadedFile{
+				Filename: upload.Filename,
+				Size:     upload.Size,
+				SHA1:     upload.Sha1.String,
+				path:     path,
+			}
+			if stored[i], err = storeUploadedImage(ctx, cfg, file); err != nil {
+				respondUploadError(c, err)
+				return
+			}
+		}
+
+		// Start transaction
+		tx, err := db.Pool.Begin(ctx)
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to start transaction")
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		qtx := queries.WithTx(tx)
+
+		// New images go after the existing ones
+		nextOrder, err := nextImageOrder(ctx, qtx, projectID)
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		for i, uploadID := range req.UploadIDs {
+			// The row lock makes a concurrent attach of the same upload fail with 404
+			if _, err := qtx.GetUploadForUpdate(ctx, uploadID); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					ErrorResponse(c, http.StatusNotFound, "Upload not found", uploadID)
+					return
+				}
+				ErrorResponse(c, http.StatusInternalServerError, "Database error")
+				return
+			}
+
+			if _, err := recordUploadedImage(ctx, qtx, cfg, projectID, stored[i], nextOrder+i, false); err != nil {
+				respondUploadError(c, err)
+				return
+			}
+
+			if err := qtx.DeleteUpload(ctx, uploadID); err != nil {
+				ErrorResponse(c, http.StatusInternalServerError, "Failed to delete upload")
+				return
+			}
+		}
+
+		// Commit transaction
+		if err := tx.Commit(ctx); err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
+			return
+		}
+
+		// The data now lives in storage
+		for _, uploadID := range req.UploadIDs {
+			if err := storage.Chunks.Remove(uploadID); err != nil {
+				log.Printf("Failed to remove upload file %s: %v", uploadID, err)
+			}
+		}
+
+		// Start processing the uploaded images
+		jobs.Notify()
+
+		respondWithProject(c, queries, projectID, http.StatusCreated)
+	}
+}
+
+// getCompletedUpload loads a finalized upload, writing a 404/409 response if it is missing or unfinished
+func getCompletedUpload(c *gin.Context, queries *sqlc.Queries, uploadID string) (sqlc.Upload, bool) {
+	upload, err := queries.GetUpload(c.Request.Context(), uploadID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusNotFound, "Upload not found", uploadID)
+			return sqlc.Upload{}, false
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return sqlc.Upload{}, false
+	}
+	if upload.Status != uploadComplete {
+		ErrorResponse(c, http.StatusConflict, "Upload is not finalized", uploadID)
+		return sqlc.Upload{}, false
+	}
+	return upload, true
+}
+
+// mapSQLCUploadToModel converts a sqlc upload to the API model
+func mapSQLCUploadToModel(u sqlc.Upload) models.Upload {
+	var sha1 *string
+	if u.Sha1.Valid {
+		sha1 = &u.Sha1.String
+	}
+	return models.Upload{
+		ID:        u.ID,
+		Filename:  u.Filename,
+		Size:      u.Size,
+		Offset:    u.Received,
+		Status:    u.Status,
+		SHA1:      sha1,
+		ExpiresAt: u.ExpiresAt.Time,
+		CreatedAt: u.CreatedAt.Time,
+	}
+}
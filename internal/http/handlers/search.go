@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/models"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// maxSearchHits caps ?limit= of the admin search (hits per entity type)
+const maxSearchHits = 20
+
+// snippetTags restores the <mark> tags of ts_headline after HTML escaping
+var snippetTags = strings.NewReplacer("&lt;mark&gt;", "<mark>", "&lt;/mark&gt;", "</mark>")
+
+// searchQuery turns a search term into a tsquery for the unaccent_simple text search configuration.
+// Every word must match the start of a word, so results show up while typing:
+// "stamb Čačak" becomes "stamb:* & čačak:*". Returns "" when the term has no letters or digits.
+func searchQuery(term string) string {
+	words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// highlightSnippet escapes a ts_headline fragment for HTML, keeping its <mark> tags
+func highlightSnippet(snippet string) string {
+	return snippetTags.Replace(html.EscapeString(snippet))
+}
+
+// attachProjectSnippets sets the snippet of the given projects: their text matching the tsquery,
+// with the matches highlighted. Does nothing when the list was not searched
+func attachProjectSnippets(ctx context.Context, queries *sqlc.Queries, tsquery string, projects []models.Project) error {
+	if tsquery == "" || len(projects) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(projects))
+	for i, p := range projects {
+		ids[i] = p.ID
+	}
+
+	rows, err := queries.ListProjectSearchSnippets(ctx, sqlc.ListProjectSearchSnippetsParams{
+		Column1: ids,
+		Column2: tsquery,
+	})
+	if err != nil {
+		return err
+	}
+
+	snippets := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		snippets[row.ProjectID] = highlightSnippet(row.Snippet)
+	}
+	for i := range projects {
+		if snippet, ok := snippets[projects[i].ID]; ok {
+			projects[i].Snippet = &snippet
+		}
+	}
+	return nil
+}
+
+// Search searches projects, testimonials, visitor messages, users and static texts at once (?q=).
+// Hits are grouped by entity type, best matches first, up to ?limit= per type (5 by default)
+func Search(c *gin.Context) {
+	params := ParseQueryParams(c)
+	tsquery := searchQuery(params.Search)
+	if tsquery == "" {
+		ErrorResponse(c, http.StatusBadRequest, "Search query is required", "q must contain letters or digits")
+		return
+	}
+
+	limit := 5
+	if limitStr := c.Query("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid limit", "limit must be a positive integer")
+			return
+		}
+		limit = min(l, maxSearchHits)
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	response := models.SearchResponse{
+		Query:           params.Search,
+		Testimonials:    models.SearchGroup{Hits: []models.SearchHit{}},
+		VisitorMessages: models.SearchGroup{Hits: []models.SearchHit{}},
+		Users:           models.SearchGroup{Hits: []models.SearchHit{}},
+		StaticTexts:     models.SearchGroup{Hits: []models.SearchHit{}},
+	}
+
+	var err error
+	response.Projects, err = searchProjects(ctx, queries, tsquery, limit)
+	if err != nil {
+		c.Error(err)
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	testimonials, err := queries.SearchTestimonials(ctx, sqlc.SearchTestimonialsParams{
+		Column1: tsquery,
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		c.Error(err)
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+	for _, t := range testimonials {
+		addSearchHit(&response.Testimonials, t.ID, t.Title, t.Snippet, t.Total)
+	}
+
+	messages, err := queries.SearchVisitorMessages(ctx, sqlc.SearchVisitorMessagesParams{
+		Column1: tsquery,
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		c.Error(err)
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+	for _, m := range messages {
+		addSearchHit(&response.VisitorMessages, m.ID, m.Title, m.Snippet, m.Total)
+	}
+
+	users, err := queries.SearchUsers(ctx, sqlc.SearchUsersParams{
+		Column1: tsquery,
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		c.Error(err)
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+	for _, u := range users {
+		addSearchHit(&response.Users, u.ID, u.Title, u.Snippet, u.Total)
+	}
+
+	staticTexts, err := queries.SearchStaticTexts(ctx, sqlc.SearchStaticTextsParams{
+		Column1: tsquery,
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		c.Error(err)
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+	for _, st := range staticTexts {
+		addSearchHit(&response.StaticTexts, st.ID, st.Title, st.Snippet, st.Total)
+	}
+
+	SuccessResponse(c, http.StatusOK, response)
+}
+
+// searchProjects returns the best matching projects for the admin search,
+// using the same full-text search and ranking as GetProjects
+func searchProjects(ctx context.Context, queries *sqlc.Queries, tsquery string, limit int) (models.SearchGroup, error) {
+	group := models.SearchGroup{Hits: []models.SearchHit{}}
+
+	projects, err := queries.ListProjectsWithSearch(ctx, sqlc.ListProjectsWithSearchParams{
+		Column1: tsquery,
+		Column3: "asc",
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		return group, fmt.Errorf("search projects: %w", err)
+	}
+	if len(projects) == 0 {
+		return group, nil
+	}
+
+	group.Total, err = queries.CountProjectsWithSearch(ctx, sqlc.CountProjectsWithSearchParams{
+		Column1: tsquery,
+	})
+	if err != nil {
+		return group, fmt.Errorf("count project matches: %w", err)
+	}
+
+	ids := make([]int64, len(projects))
+	for i, p := range projects {
+		ids[i] = p.ID
+	}
+	rows, err := queries.ListProjectSearchSnippets(ctx, sqlc.ListProjectSearchSnippetsParams{
+		Column1: ids,
+		Column2: tsquery,
+	})
+	if err != nil {
+		return group, fmt.Errorf("list project search snippets: %w", err)
+	}
+	snippets := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		snippets[row.ProjectID] = row.Snippet
+	}
+
+	for _, p := range projects {
+		addSearchHit(&group, p.ID, p.Name, snippets[p.ID], group.Total)
+	}
+	return group, nil
+}
+
+// addSearchHit appends a hit to the group. total is the group's number of matches
+func addSearchHit(group *models.SearchGroup, id int64, title, snippet string, total int64) {
+	group.Total = total
+	group.Hits = append(group.Hits, models.SearchHit{
+		ID:      id,
+		Title:   title,
+		Snippet: highlightSnippet(snippet),
+	})
+}
@@ -16,7 +16,8 @@ type UpdateStaticTextRequest struct {
 	Content string `json:"content" binding:"required"`
 }
 
-// GetStaticTexts returns paginated static texts (10 per page)
+// GetStaticTexts returns paginated static texts (?page=&per_page=, 10 per page by default)
+// With ?cursor= or ?limit= the list is paginated by cursor instead (see parseCursorRequest)
 func GetStaticTexts(c *gin.Context) {
 	pageStr := c.DefaultQuery("page", "1")
 	page, err := strconv.Atoi(pageStr)
@@ -24,9 +25,44 @@ func GetStaticTexts(c *gin.Context) {
 		page = 1
 	}
 
+	cursorReq, cursorMode, ok := parseCursorRequest(c, "created_at:desc", 10, maxPageLimit)
+	if !ok {
+		return
+	}
+
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
-	perPage := 10
+
+	if cursorMode {
+		staticTexts, err := queries.ListStaticTextsKeyset(ctx, sqlc.ListStaticTextsKeysetParams{
+			Column1: cursorReq.before(),
+			Column2: cursorReq.afterTime(),
+			Column3: cursorReq.afterID(),
+			Limit:   int32(cursorReq.Limit + 1),
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		staticTexts, next, prev := paginateCursor(cursorReq, staticTexts, func(st sqlc.StaticText) pageCursor {
+			return timeCursor(st.ID, st.CreatedAt)
+		})
+		staticTextModels := make([]models.StaticText, len(staticTexts))
+		for i, st := range staticTexts {
+			staticTextModels[i] = mapSQLCStaticTextToModel(st)
+		}
+
+		SuccessResponse(c, http.StatusOK, models.CursorPaginationResponse{
+			Data:       staticTextModels,
+			Limit:      cursorReq.Limit,
+			NextCursor: next,
+			PrevCursor: prev,
+		})
+		return
+	}
+
+	perPage := ParsePerPage(c, 10, maxPageLimit)
 	offset := (page - 1) * perPage
 
 	staticTexts, err := queries.ListStaticTexts(ctx, sqlc.ListStaticTextsParams{
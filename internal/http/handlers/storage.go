@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+// resizeGroup deduplicates concurrent resizes of the same image and size
+var resizeGroup singleflight.Group
+
+// negotiableFormats lists modern formats (best first) that may be served in place of a JPEG/PNG
+var negotiableFormats = []struct {
+	format   string
+	mimeType string
+}{
+	{storage.FormatWebP, "image/webp"},
+}
+
+// ServeStorageImage streams an uploaded image from the configured storage backend.
+// JPEG/PNG requests are answered with a stored WebP copy when the client accepts it.
+// With ?w=&h=&fit=&fmt= the image is resized on the fly (see serveResizedImage)
+func ServeStorageImage(cfg *config.Config) gin.HandlerFunc {
+	// Bounds how many resizes decode an original at the same time
+	resizeSlots := make(chan struct{}, cfg.Resize.MaxConcurrent)
+
+	return func(c *gin.Context) {
+		filename := c.Param("file")
+		if filename == "" || strings.ContainsAny(filename, `/\`) {
+			ErrorResponse(c, http.StatusNotFound, "File not found")
+			return
+		}
+
+		if c.Query("w") != "" || c.Query("h") != "" || c.Query("fit") != "" || c.Query("fmt") != "" {
+			serveResizedImage(c, cfg, "img/"+filename, resizeSlots)
+			return
+		}
+
+		serveStoredFile(c, negotiateImageKey(c, "img/"+filename))
+	}
+}
+
+// serveStoredFile streams a file from the storage backend
+func serveStoredFile(c *gin.Context, key string) {
+	// Backends with their own public URL (e.g. a CDN in front of S3) are served from there
+	if publicURL := storage.Store.URL(key); publicURL != storage.PathForKey(key) {
+		c.Redirect(http.StatusFound, publicURL)
+		return
+	}
+
+	file, info, err := storage.Store.Get(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			ErrorResponse(c, http.StatusNotFound, "File not found")
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	// Files are content-addressed, so they can be cached forever
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if info.ContentType != "" {
+		c.Header("Content-Type", info.ContentType)
+	}
+
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime, seeker)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, info.Size, info.ContentType, file, nil)
+}
+
+// serveResizedImage resizes an original on request (?w=&h=&fit=cover|contain&fmt=jpeg|png|webp).
+// Only sizes from the configured allow-list of originals still used by a project image are served;
+// results are kept in the LRU disk cache. At most cap(slots) resizes decode an image at a time
+func serveResizedImage(c *gin.Context, cfg *config.Config, key string, slots chan struct{}) {
+	opts, err := parseResizeOptions(c, cfg)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid resize parameters", err.Error())
+		return
+	}
+
+	// Cached copies of deleted images must not outlive them
+	inUse, err := sqlc.New(db.Pool).StoredFileInUse(c.Request.Context(), storage.PathForKey(key))
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if !inUse {
+		ErrorResponse(c, http.StatusNotFound, "File not found")
+		return
+	}
+
+	// Without an explicit format, pick the best one the client accepts
+	if opts.Format == "" {
+		c.Header("Vary", "Accept")
+		switch {
+		case cfg.Images.WebP && acceptsMediaType(c.GetHeader("Accept"), "image/webp"):
+			opts.Format = storage.FormatWebP
+		case strings.EqualFold(path.Ext(key), ".png"):
+			opts.Format = storage.FormatPNG
+		default:
+			opts.Format = storage.FormatJPEG
+		}
+	}
+
+	// Originals are content-addressed, so resized copies can be cached forever as well
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+
+	name := opts.CacheName(key)
+	if cached, ok := storage.ResizeCache.Get(name); ok {
+		if file, err := os.Open(cached); err == nil {
+			defer file.Close()
+			c.Header("Content-Type", opts.ContentType())
+			http.ServeContent(c.Writer, c.Request, name, time.Time{}, file)
+			return
+		}
+	}
+
+	// Concurrent requests for the same uncached size share one resize
+	// (the shared work must not be cancelled when the first client disconnects)
+	ctx := context.WithoutCancel(c.Request.Context())
+	result, err, _ := resizeGroup.Do(name, func() (interface{}, error) {
+		slots <- struct{}{}
+		defer func() { <-slots }()
+
+		file, _, err := storage.Store.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		original, err := io.ReadAll(file)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := storage.ResizeImage(original, opts, cfg.Images, cfg.Resize.MaxPixels)
+		if err != nil {
+			return nil, err
+		}
+		if err := storage.ResizeCache.Put(name, data); err != nil {
+			log.Printf("Failed to cache resized image %s: %v", name, err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			ErrorResponse(c, http.StatusNotFound, "File not found")
+			return
+		}
+		if errors.Is(err, storage.ErrImageTooLarge) {
+			ErrorResponse(c, http.StatusUnprocessableEntity, "Image too large to resize")
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to resize image")
+		return
+	}
+
+	c.Data(http.StatusOK, opts.ContentType(), result.([]byte))
+}
+
+// parseResizeOptions reads and validates the resize query parameters against the allow-list
+func parseResizeOptions(c *gin.Context, cfg *config.Config) (storage.ResizeOptions, error) {
+	var opts storage.ResizeOptions
+	var err error
+
+	if w := c.Query("w"); w != "" {
+		if opts.Width, err = strconv.Atoi(w); err != nil || opts.Width < 0 {
+			return opts, fmt.Errorf("w must be a non-negative integer")
+		}
+	}
+	if h := c.Query("h"); h != "" {
+		if opts.Height, err = strconv.Atoi(h); err != nil || opts.Height < 0 {
+			return opts, fmt.Errorf("h must be a non-negative integer")
+		}
+	}
+	if opts.Width == 0 && opts.Height == 0 {
+		return opts, fmt.Errorf("w or h is required")
+	}
+
+	allowed := false
+	for _, size := range cfg.Resize.Sizes {
+		if size.Width == opts.Width && size.Height == opts.Height {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return opts, fmt.Errorf("size %dx%d is not allowed", opts.Width, opts.Height)
+	}
+
+	switch fit := c.DefaultQuery("fit", storage.FitCover); fit {
+	case storage.FitCover, storage.FitContain:
+		opts.Fit = fit
+	default:
+		return opts, fmt.Errorf("fit must be one of: cover, contain")
+	}
+	// Fit only matters when both dimensions are set
+	if opts.Width == 0 || opts.Height == 0 {
+		opts.Fit = ""
+	}
+
+	switch format := strings.ToLower(c.Query("fmt")); format {
+	case "":
+	case "jpg", storage.FormatJPEG:
+		opts.Format = storage.FormatJPEG
+	case storage.FormatPNG, storage.FormatWebP:
+		opts.Format = format
+	default:
+		return opts, fmt.Errorf("fmt must be one of: jpeg, png, webp")
+	}
+
+	return opts, nil
+}
+
+// negotiateImageKey returns the key of the best stored format for the request's Accept header.
+// Alternates are looked up in project_image_variants (where the image workers record them)
+// instead of asking the storage backend on every request
+func negotiateImageKey(c *gin.Context, key string) string {
+	ext := strings.ToLower(path.Ext(key))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return key
+	}
+
+	// The response depends on Accept, caches must key on it
+	c.Header("Vary", "Accept")
+
+	accept := c.GetHeader("Accept")
+	for _, f := range negotiableFormats {
+		if !acceptsMediaType(accept, f.mimeType) {
+			continue
+		}
+		alternate := storage.AlternateKey(key, f.format)
+		exists, err := sqlc.New(db.Pool).ProjectImageVariantExists(c.Request.Context(), sqlc.ProjectImageVariantExistsParams{
+			Url:    storage.PathForKey(alternate),
+			Format: f.format,
+		})
+		if err == nil && exists {
+			return alternate
+		}
+	}
+
+	return key
+}
+
+// acceptsMediaType reports whether an Accept header explicitly lists mimeType with a non-zero
+// quality ("image/webp;q=0" refuses it). Wildcards such as image/* don't count: browsers that
+// send them without naming the format may not be able to decode it
+func acceptsMediaType(accept, mimeType string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), mimeType) {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return false
+			}
+			q = parsed
+		}
+		return q > 0
+	}
+	return false
+}
+
+// ServePlaceholder renders a project image's blurhash as a small blurred PNG
+// (optional ?w=&h=, default 32x32, max 128x128). Only images of published projects are served;
+// images without a blurhash yet (still processing or awaiting the backfill) are not found
+func ServePlaceholder(c *gin.Context) {
+	imageID, err := strconv.ParseInt(c.Param("imageId"), 10, 64)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid project image ID")
+		return
+	}
+
+	width, height := storage.PlaceholderSize, storage.PlaceholderSize
+	if w := c.Query("w"); w != "" {
+		if width, err = strconv.Atoi(w); err != nil || width < 1 || width > storage.MaxPlaceholderSize {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid width")
+			return
+		}
+	}
+	if h := c.Query("h"); h != "" {
+		if height, err = strconv.Atoi(h); err != nil || height < 1 || height > storage.MaxPlaceholderSize {
+			ErrorResponse(c, http.StatusBadRequest, "Invalid height")
+			return
+		}
+	}
+
+	queries := sqlc.New(db.Pool)
+	ctx := c.Request.Context()
+
+	hash, err := queries.GetPublishedProjectImageBlurHash(ctx, imageID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ErrorResponse(c, http.StatusNotFound, "Project image not found")
+			return
+		}
+		ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if !hash.Valid {
+		ErrorResponse(c, http.StatusNotFound, "Placeholder not available")
+		return
+	}
+
+	data, err := storage.RenderBlurHashPNG(hash.String, width, height)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Failed to render placeholder")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "image/png", data)
+}
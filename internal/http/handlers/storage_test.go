@@ -0,0 +1,35 @@
+package handlers
+
+import "testing"
+
+func TestAcceptsMediaType(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   bool
+	}{
+		{"empty", "", false},
+		{"exact", "image/webp", true},
+		{"browser", "image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8", true},
+		{"with quality", "image/webp;q=0.9,image/jpeg", true},
+		{"spaces", "image/jpeg , image/webp ; q=0.5", true},
+		{"case insensitive", "Image/WebP", true},
+		{"quality zero", "image/webp;q=0,image/*", false},
+		{"quality zero decimals", "image/webp; q=0.000", false},
+		{"uppercase q", "image/webp;Q=0", false},
+		{"other params", "image/webp;level=1;q=0.4", true},
+		{"malformed quality", "image/webp;q=abc", false},
+		{"image wildcard", "image/*", false},
+		{"any wildcard", "*/*", false},
+		{"other type", "image/jpeg,image/png", false},
+		{"prefix only", "image/webpx", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acceptsMediaType(tt.accept, "image/webp"); got != tt.want {
+				t.Errorf("acceptsMediaType(%q) = %v, want %v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
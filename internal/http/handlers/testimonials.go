@@ -12,7 +12,8 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
-// GetTestimonials returns paginated testimonials (10 per page)
+// GetTestimonials returns paginated testimonials (?page=&per_page=, 10 per page by default)
+// With ?cursor= or ?limit= the list is paginated by cursor instead (see parseCursorRequest)
 func GetTestimonials(c *gin.Context) {
 	pageStr := c.DefaultQuery("page", "1")
 	page, err := strconv.Atoi(pageStr)
@@ -20,9 +21,44 @@ func GetTestimonials(c *gin.Context) {
 		page = 1
 	}
 
+	cursorReq, cursorMode, ok := parseCursorRequest(c, "created_at:desc", 10, maxPageLimit)
+	if !ok {
+		return
+	}
+
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
-	perPage := 10
+
+	if cursorMode {
+		testimonials, err := queries.ListTestimonialsKeyset(ctx, sqlc.ListTestimonialsKeysetParams{
+			Column1: cursorReq.before(),
+			Column2: cursorReq.afterTime(),
+			Column3: cursorReq.afterID(),
+			Limit:   int32(cursorReq.Limit + 1),
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		testimonials, next, prev := paginateCursor(cursorReq, testimonials, func(t sqlc.Testimonial) pageCursor {
+			return timeCursor(t.ID, t.CreatedAt)
+		})
+		testimonialModels := make([]models.Testimonial, len(testimonials))
+		for i, t := range testimonials {
+			testimonialModels[i] = mapSQLCTestimonialToModel(t)
+		}
+
+		SuccessResponse(c, http.StatusOK, models.CursorPaginationResponse{
+			Data:       testimonialModels,
+			Limit:      cursorReq.Limit,
+			NextCursor: next,
+			PrevCursor: prev,
+		})
+		return
+	}
+
+	perPage := ParsePerPage(c, 10, maxPageLimit)
 	offset := (page - 1) * perPage
 
 	testimonials, err := queries.ListTestimonials(ctx, sqlc.ListTestimonialsParams{
@@ -92,6 +128,11 @@ func CreateTestimonial(c *gin.Context) {
 		return
 	}
 
+	if err := validateSchedule(testimonial.PublishAt, testimonial.UnpublishAt); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid schedule", err.Error())
+		return
+	}
+
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
 
@@ -100,6 +141,8 @@ func CreateTestimonial(c *gin.Context) {
 		Profession:  testimonial.Profession,
 		Testimonial: testimonial.Testimonial,
 		Status:      testimonial.Status,
+		PublishAt:   pgtypeTimestamptzPtr(testimonial.PublishAt),
+		UnpublishAt: pgtypeTimestamptzPtr(testimonial.UnpublishAt),
 	})
 	if err != nil {
 		ErrorResponse(c, http.StatusInternalServerError, "Failed to create testimonial")
@@ -124,6 +167,11 @@ func UpdateTestimonial(c *gin.Context) {
 		return
 	}
 
+	if err := validateSchedule(testimonial.PublishAt, testimonial.UnpublishAt); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "Invalid schedule", err.Error())
+		return
+	}
+
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
 
@@ -145,6 +193,8 @@ func UpdateTestimonial(c *gin.Context) {
 		Profession:  testimonial.Profession,
 		Testimonial: testimonial.Testimonial,
 		Status:      testimonial.Status,
+		PublishAt:   pgtypeTimestamptzPtr(testimonial.PublishAt),
+		UnpublishAt: pgtypeTimestamptzPtr(testimonial.UnpublishAt),
 	})
 	if err != nil {
 		ErrorResponse(c, http.StatusInternalServerError, "Failed to update testimonial")
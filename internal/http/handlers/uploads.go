@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// maxFormValuesSize caps the combined size of the non-file fields of an upload form
+const maxFormValuesSize = 1 << 20
+
+// uploadedFile is a file from a multipart upload, spooled to a temporary file
+type uploadedFile struct {
+	Filename string
+	Size     int64
+	SHA1     string // hex SHA1 of the uploaded bytes, computed while streaming
+	path     string
+}
+
+// Open opens the spooled file for reading
+func (f *uploadedFile) Open() (io.ReadCloser, error) {
+	return os.Open(f.path)
+}
+
+// uploadForm is a parsed multipart form whose files live on disk instead of in memory
+type uploadForm struct {
+	Value map[string][]string
+	File  map[string][]*uploadedFile
+}
+
+// RemoveAll deletes the temporary files of the form
+func (f *uploadForm) RemoveAll() {
+	for _, files := range f.File {
+		for _, file := range files {
+			os.Remove(file.path)
+		}
+	}
+}
+
+// parseUploadForm streams a multipart request, writing each file to a temporary file while hashing it,
+// and enforces the configured file size, file count and request size limits (413 errors naming the file).
+// Callers must call RemoveAll on the returned form
+func parseUploadForm(c *gin.Context, limits config.UploadConfig) (*uploadForm, error) {
+	if c.Request.ContentLength > limits.MaxRequestSize {
+		return nil, &imageUploadError{http.StatusRequestEntityTooLarge, "Request too large",
+			fmt.Errorf("request body of %d bytes exceeds the maximum of %d bytes", c.Request.ContentLength, limits.MaxRequestSize)}
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxRequestSize)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, &imageUploadError{http.StatusBadRequest, "Invalid multipart form", err}
+	}
+
+	form := &uploadForm{
+		Value: make(map[string][]string),
+		File:  make(map[string][]*uploadedFile),
+	}
+	fileCount := 0
+	var valuesSize int64
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			form.RemoveAll()
+			return nil, uploadReadError(err, "", limits)
+		}
+
+		name := part.FormName()
+		if name == "" {
+			part.Close()
+			continue
+		}
+
+		// Regular form field
+		if part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, maxFormValuesSize-valuesSize+1))
+			part.Close()
+			if err != nil {
+				form.RemoveAll()
+				return nil, uploadReadError(err, "", limits)
+			}
+			valuesSize += int64(len(value))
+			if valuesSize > maxFormValuesSize {
+				form.RemoveAll()
+				return nil, &imageUploadError{http.StatusRequestEntityTooLarge, "Form fields too large",
+					fmt.Errorf("form fields exceed the maximum of %d bytes", maxFormValuesSize)}
+			}
+			form.Value[name] = append(form.Value[name], string(value))
+			continue
+		}
+
+		fileCount++
+		if fileCount > limits.MaxFiles {
+			part.Close()
+			form.RemoveAll()
+			return nil, &imageUploadError{http.StatusRequestEntityTooLarge, "Too many files",
+				fmt.Errorf("%s: at most %d files can be uploaded per request", part.FileName(), limits.MaxFiles)}
+		}
+
+		file, err := spoolUploadedFile(part, limits)
+		part.Close()
+		if err != nil {
+			form.RemoveAll()
+			return nil, err
+		}
+		form.File[name] = append(form.File[name], file)
+	}
+
+	return form, nil
+}
+
+// spoolUploadedFile copies a file part to a temporary file, hashing it on the way
+func spoolUploadedFile(part *multipart.Part, limits config.UploadConfig) (*uploadedFile, error) {
+	tmp, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, &imageUploadError{http.StatusInternalServerError, "Failed to store upload", err}
+	}
+
+	hash := sha1.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(part, limits.MaxFileSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return nil, uploadReadError(err, part.FileName(), limits)
+	}
+	if size > limits.MaxFileSize {
+		os.Remove(tmp.Name())
+		return nil, &imageUploadError{http.StatusRequestEntityTooLarge, "File too large",
+			fmt.Errorf("%s exceeds the maximum file size of %d bytes", part.FileName(), limits.MaxFileSize)}
+	}
+
+	return &uploadedFile{
+		Filename: part.FileName(),
+		Size:     size,
+		SHA1:     hex.EncodeToString(hash.Sum(nil)),
+		path:     tmp.Name(),
+	}, nil
+}
+
+// uploadReadError maps an error while reading the request body to a response,
+// naming the file being read when the request size limit was hit
+func uploadReadError(err error, filename string, limits config.UploadConfig) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		if filename != "" {
+			err = fmt.Errorf("request exceeded the maximum size of %d bytes while reading %s", limits.MaxRequestSize, filename)
+		} else {
+			err = fmt.Errorf("request exceeds the maximum size of %d bytes", limits.MaxRequestSize)
+		}
+		return &imageUploadError{http.StatusRequestEntityTooLarge, "Request too large", err}
+	}
+	return &imageUploadError{http.StatusBadRequest, "Invalid multipart form", err}
+}
@@ -26,7 +26,8 @@ type UpdateUserRequest struct {
 	Password string `json:"password,omitempty" binding:"omitempty,min=8"`
 }
 
-// GetUsers returns paginated users (10 per page)
+// GetUsers returns paginated users (?page=&per_page=, 10 per page by default)
+// With ?cursor= or ?limit= the list is paginated by cursor instead (see parseCursorRequest)
 func GetUsers(c *gin.Context) {
 	pageStr := c.DefaultQuery("page", "1")
 	page, err := strconv.Atoi(pageStr)
@@ -34,9 +35,44 @@ func GetUsers(c *gin.Context) {
 		page = 1
 	}
 
+	cursorReq, cursorMode, ok := parseCursorRequest(c, "created_at:desc", 10, maxPageLimit)
+	if !ok {
+		return
+	}
+
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
-	perPage := 10
+
+	if cursorMode {
+		users, err := queries.ListUsersKeyset(ctx, sqlc.ListUsersKeysetParams{
+			Column1: cursorReq.before(),
+			Column2: cursorReq.afterTime(),
+			Column3: cursorReq.afterID(),
+			Limit:   int32(cursorReq.Limit + 1),
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		users, next, prev := paginateCursor(cursorReq, users, func(u sqlc.User) pageCursor {
+			return timeCursor(u.ID, u.CreatedAt)
+		})
+		userModels := make([]models.User, len(users))
+		for i, u := range users {
+			userModels[i] = mapSQLCUserToModel(u)
+		}
+
+		SuccessResponse(c, http.StatusOK, models.CursorPaginationResponse{
+			Data:       userModels,
+			Limit:      cursorReq.Limit,
+			NextCursor: next,
+			PrevCursor: prev,
+		})
+		return
+	}
+
+	perPage := ParsePerPage(c, 10, maxPageLimit)
 	offset := (page - 1) * perPage
 
 	// Get users
@@ -10,7 +10,8 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// GetVisitorMessages returns paginated visitor messages (10 per page)
+// GetVisitorMessages returns paginated visitor messages (?page=&per_page=, 10 per page by default)
+// With ?cursor= or ?limit= the list is paginated by cursor instead (see parseCursorRequest)
 func GetVisitorMessages(c *gin.Context) {
 	pageStr := c.DefaultQuery("page", "1")
 	page, err := strconv.Atoi(pageStr)
@@ -18,9 +19,44 @@ func GetVisitorMessages(c *gin.Context) {
 		page = 1
 	}
 
+	cursorReq, cursorMode, ok := parseCursorRequest(c, "created_at:desc", 10, maxPageLimit)
+	if !ok {
+		return
+	}
+
 	queries := sqlc.New(db.Pool)
 	ctx := c.Request.Context()
-	perPage := 10
+
+	if cursorMode {
+		messages, err := queries.ListVisitorMessagesKeyset(ctx, sqlc.ListVisitorMessagesKeysetParams{
+			Column1: cursorReq.before(),
+			Column2: cursorReq.afterTime(),
+			Column3: cursorReq.afterID(),
+			Limit:   int32(cursorReq.Limit + 1),
+		})
+		if err != nil {
+			ErrorResponse(c, http.StatusInternalServerError, "Database error")
+			return
+		}
+
+		messages, next, prev := paginateCursor(cursorReq, messages, func(m sqlc.VisitorMessage) pageCursor {
+			return timeCursor(m.ID, m.CreatedAt)
+		})
+		messageModels := make([]models.VisitorMessage, len(messages))
+		for i, m := range messages {
+			messageModels[i] = mapSQLCVisitorMessageToModel(m)
+		}
+
+		SuccessResponse(c, http.StatusOK, models.CursorPaginationResponse{
+			Data:       messageModels,
+			Limit:      cursorReq.Limit,
+			NextCursor: next,
+			PrevCursor: prev,
+		})
+		return
+	}
+
+	perPage := ParsePerPage(c, 10, maxPageLimit)
 	offset := (page - 1) * perPage
 
 	messages, err := queries.ListVisitorMessages(ctx, sqlc.ListVisitorMessagesParams{
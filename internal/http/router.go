@@ -15,8 +15,10 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	router.Use(middleware.CORSMiddleware())
 	router.Use(middleware.ErrorHandlerMiddleware())
 
-	// Serve static files
-	router.Static("/storage/img", "./storage/public/img")
+	// Serve uploaded files from the configured storage backend
+	router.GET("/storage/img/:file", handlers.ServeStorageImage(cfg))
+	router.HEAD("/storage/img/:file", handlers.ServeStorageImage(cfg))
+	router.GET("/storage/placeholder/:imageId", handlers.ServePlaceholder)
 
 	// Public routes (no auth)
 	router.GET("/ping", handlers.Ping)
@@ -25,8 +27,11 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	{
 		public.GET("/projects", handlers.GetPublicProjects)
 		public.GET("/projects/highlighted", handlers.GetHighlightedProjects)
+		public.GET("/projects/search", handlers.SearchPublicProjects)
 		public.GET("/async/projects/page", handlers.GetPublicProjectsPaginated)
 		public.GET("/projects/:id", handlers.GetPublicProject)
+		public.GET("/projects/by-slug/:slug", handlers.GetPublicProjectBySlug)
+		public.GET("/categories", handlers.GetPublicCategories)
 		public.GET("/testimonials", handlers.GetPublicTestimonials)
 		public.POST("/testimonials", handlers.CreatePublicTestimonial)
 		public.GET("/static-texts", handlers.GetPublicStaticTexts)
@@ -56,13 +61,40 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		admin.GET("/projects", handlers.GetProjects)
 		admin.GET("/projects/:id", handlers.GetProject)
 		admin.POST("/projects", handlers.CreateProject(cfg))
+		admin.PUT("/projects/order", handlers.ReorderProjects)
 		admin.PUT("/projects/:id", handlers.UpdateProject(cfg))
 		admin.PUT("/projects/:id/highlight/toggle", handlers.ToggleHighlight)
+		admin.PUT("/projects/:id/move", handlers.MoveProject)
+		admin.POST("/projects/:id/publish", handlers.PublishProject)
+		admin.POST("/projects/:id/unpublish", handlers.UnpublishProject)
+		admin.POST("/projects/:id/archive", handlers.ArchiveProject)
+		admin.POST("/projects/:id/restore", handlers.RestoreProject)
 		admin.DELETE("/projects/:id", handlers.DeleteProject(cfg))
+		admin.POST("/projects/:id/images", handlers.AddProjectImages(cfg))
+		admin.PUT("/projects/:id/images/order", handlers.ReorderProjectImages)
+		admin.PUT("/projects/:id/images/:imageId", handlers.UpdateProjectImageDetails)
+		admin.PUT("/projects/:id/images/:imageId/highlight", handlers.SetProjectCoverImage)
+		admin.DELETE("/projects/:id/images/:imageId", handlers.DeleteProjectImageByID)
+		admin.POST("/projects/:id/images/uploads", handlers.AttachUploadedImages(cfg))
+
+		// Resumable uploads
+		admin.POST("/uploads", handlers.CreateUpload(cfg))
+		admin.GET("/uploads/:id", handlers.GetUpload)
+		admin.PATCH("/uploads/:id", handlers.AppendUploadChunk(cfg))
+		admin.POST("/uploads/:id/finalize", handlers.FinalizeUpload)
+		admin.DELETE("/uploads/:id", handlers.DeleteUpload)
 
 		// Project Images
 		admin.GET("/project-images/:id", handlers.GetProjectImage)
 
+		// Categories
+		admin.GET("/categories", handlers.GetCategories)
+		admin.GET("/categories/:id", handlers.GetCategory)
+		admin.POST("/categories", handlers.CreateCategory)
+		admin.PUT("/categories/order", handlers.ReorderCategories)
+		admin.PUT("/categories/:id", handlers.UpdateCategory)
+		admin.DELETE("/categories/:id", handlers.DeleteCategory)
+
 		// Testimonials
 		admin.GET("/testimonials", handlers.GetTestimonials)
 		admin.GET("/testimonials/:id", handlers.GetTestimonial)
@@ -85,9 +117,15 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		// Configurations
 		admin.PUT("/configs/:key", handlers.UpdateConfig)
 
+		// Publishing scheduler audit trail
+		admin.GET("/publication-events", handlers.GetPublicationEvents)
+
 		// Visitor Messages
 		admin.GET("/visitor-messages", handlers.GetVisitorMessages)
 		admin.DELETE("/visitor-messages/:id", handlers.DeleteVisitorMessage)
+
+		// Search across all content
+		admin.GET("/search", handlers.Search)
 	}
 
 	return router
@@ -0,0 +1,259 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Image processing states stored in project_images.processing_status
+const (
+	ImageProcessing = "processing"
+	ImageReady      = "ready"
+	ImageFailed     = "failed"
+)
+
+const (
+	// staleJobTimeout is how long a job may stay locked before it is assumed
+	// abandoned (e.g. the server was restarted mid-job) and picked up again
+	staleJobTimeout = 10 * time.Minute
+
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+// wake signals idle workers that jobs were enqueued
+var wake = make(chan struct{}, 1)
+
+// Notify wakes an idle worker so newly enqueued jobs are processed right away
+// instead of on the next poll. Call it after the enqueueing transaction commits
+func Notify() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// RunImageWorkers processes queued image jobs with cfg.ImageJobs.Workers workers until ctx is cancelled
+func RunImageWorkers(ctx context.Context, cfg *config.Config) {
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.ImageJobs.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runWorker(ctx, cfg)
+		}()
+	}
+	wg.Wait()
+}
+
+func runWorker(ctx context.Context, cfg *config.Config) {
+	ticker := time.NewTicker(cfg.ImageJobs.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the queue before going idle
+		for processNextJob(ctx, cfg) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// processNextJob claims and runs one due job, returning false when the queue is empty
+func processNextJob(ctx context.Context, cfg *config.Config) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	queries := sqlc.New(db.Pool)
+
+	job, err := queries.ClaimImageJob(ctx, pgtype.Interval{Microseconds: staleJobTimeout.Microseconds(), Valid: true})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil {
+			log.Printf("Image jobs: failed to claim job: %v", err)
+		}
+		return false
+	}
+
+	// There may be more work; let another idle worker look too
+	Notify()
+
+	if err := ProcessImage(ctx, job.ProjectImageID, cfg); err != nil {
+		recordFailure(ctx, queries, job, err, cfg)
+		return true
+	}
+
+	if err := queries.DeleteImageJob(ctx, job.ID); err != nil {
+		log.Printf("Image jobs: failed to delete finished job %d: %v", job.ID, err)
+	}
+	return true
+}
+
+// recordFailure schedules a retry with exponential backoff, or marks the job and image
+// as failed once the attempts are used up
+func recordFailure(ctx context.Context, queries *sqlc.Queries, job sqlc.ImageJob, jobErr error, cfg *config.Config) {
+	lastError := pgtype.Text{String: jobErr.Error(), Valid: true}
+
+	if int(job.Attempts) < cfg.ImageJobs.MaxAttempts {
+		delay := retryBaseDelay << (job.Attempts - 1)
+		if delay > retryMaxDelay || delay <= 0 {
+			delay = retryMaxDelay
+		}
+		log.Printf("Image jobs: image %d failed (attempt %d/%d), retrying in %s: %v",
+			job.ProjectImageID, job.Attempts, cfg.ImageJobs.MaxAttempts, delay, jobErr)
+
+		err := queries.RetryImageJob(ctx, sqlc.RetryImageJobParams{
+			ID:        job.ID,
+			LastError: lastError,
+			Column3:   pgtype.Interval{Microseconds: delay.Microseconds(), Valid: true},
+		})
+		if err != nil {
+			log.Printf("Image jobs: failed to reschedule job %d: %v", job.ID, err)
+		}
+		return
+	}
+
+	log.Printf("Image jobs: image %d failed after %d attempts: %v", job.ProjectImageID, job.Attempts, jobErr)
+
+	if err := queries.FailImageJob(ctx, sqlc.FailImageJobParams{ID: job.ID, LastError: lastError}); err != nil {
+		log.Printf("Image jobs: failed to mark job %d as failed: %v", job.ID, err)
+	}
+	err := queries.UpdateProjectImageProcessingStatus(ctx, sqlc.UpdateProjectImageProcessingStatusParams{
+		ID:               job.ProjectImageID,
+		ProcessingStatus: ImageFailed,
+		ProcessingError:  lastError,
+	})
+	if err != nil {
+		log.Printf("Image jobs: failed to mark image %d as failed: %v", job.ProjectImageID, err)
+	}
+}
+
+// ProcessImage generates the blurhash, image info and width variants of a stored project image
+// and marks it as ready. Images deleted in the meantime are skipped
+func ProcessImage(ctx context.Context, imageID int64, cfg *config.Config) error {
+	queries := sqlc.New(db.Pool)
+
+	img, err := queries.GetProjectImageByID(ctx, imageID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to load image: %w", err)
+	}
+	if img.ProcessingStatus == ImageReady && img.BlurHash.Valid {
+		return nil
+	}
+
+	fileData, err := readStoredFile(ctx, img.Url)
+	if err != nil {
+		return err
+	}
+
+	info, err := storage.AnalyzeImage(fileData)
+	if err != nil {
+		return err
+	}
+
+	// Ready images queued for a missing blurhash (e.g. a new cover image) already have their variants
+	if img.ProcessingStatus == ImageReady {
+		return queries.UpdateProjectImageInfo(ctx, imageInfoParams(img.ID, info))
+	}
+
+	// Files are written before the transaction; they are content-addressed so retries overwrite them
+	variants, err := storage.GenerateVariants(ctx, fileData, img.Url, cfg.Images)
+	if err != nil {
+		return err
+	}
+
+	// Start transaction
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := queries.WithTx(tx)
+
+	err = qtx.UpdateProjectImageInfo(ctx, imageInfoParams(img.ID, info))
+	if err != nil {
+		return fmt.Errorf("failed to update image info: %w", err)
+	}
+
+	for _, v := range variants {
+		_, err := qtx.CreateProjectImageVariant(ctx, sqlc.CreateProjectImageVariantParams{
+			ProjectImageID: img.ID,
+			Width:          int32(v.Width),
+			Height:         int32(v.Height),
+			Url:            v.URL,
+			Format:         v.Format,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create image variant: %w", err)
+		}
+	}
+
+	err = qtx.UpdateProjectImageProcessingStatus(ctx, sqlc.UpdateProjectImageProcessingStatusParams{
+		ID:               img.ID,
+		ProcessingStatus: ImageReady,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update processing status: %w", err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// readStoredFile loads a file from the configured storage backend
+func readStoredFile(ctx context.Context, url string) ([]byte, error) {
+	key, err := storage.KeyFromURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	file, _, err := storage.Store.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
+// imageInfoParams converts analyzed image properties into column values
+func imageInfoParams(imageID int64, info *storage.ImageInfo) sqlc.UpdateProjectImageInfoParams {
+	return sqlc.UpdateProjectImageInfoParams{
+		ID:            imageID,
+		Width:         pgtype.Int4{Int32: int32(info.Width), Valid: true},
+		Height:        pgtype.Int4{Int32: int32(info.Height), Valid: true},
+		SizeBytes:     pgtype.Int8{Int64: info.SizeBytes, Valid: true},
+		MimeType:      pgtype.Text{String: info.MimeType, Valid: true},
+		DominantColor: pgtype.Text{String: info.DominantColor, Valid: true},
+		BlurHash:      pgtype.Text{String: info.BlurHash, Valid: true},
+	}
+}
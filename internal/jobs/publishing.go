@@ -0,0 +1,119 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/db"
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Audit trail values stored in publication_events
+const (
+	entityProject     = "project"
+	entityTestimonial = "testimonial"
+
+	actionPublished   = "published"
+	actionUnpublished = "unpublished"
+)
+
+// RunPublishScheduler applies due publish_at/unpublish_at schedules every interval until ctx is cancelled
+func RunPublishScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := ApplyPublishSchedules(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Publish scheduler failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ApplyPublishSchedules moves projects and testimonials whose publish_at/unpublish_at has passed
+// to their new status and records each change in publication_events, all in one transaction:
+//
+//   - projects: draft -> published at publish_at, published -> archived at unpublish_at
+//   - testimonials: pending -> ready at publish_at, ready -> pending at unpublish_at
+//
+// Running it concurrently from several servers is safe; the row locks taken by the
+// updates make every change happen (and be recorded) once.
+func ApplyPublishSchedules(ctx context.Context) error {
+	// Start transaction
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(db.Pool).WithTx(tx)
+	var events []sqlc.CreatePublicationEventParams
+
+	published, err := qtx.PublishScheduledProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to publish projects: %w", err)
+	}
+	for _, p := range published {
+		events = append(events, publicationEvent(entityProject, p.ID, actionPublished, "draft", "published", p.PublishAt))
+	}
+
+	unpublished, err := qtx.UnpublishScheduledProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to unpublish projects: %w", err)
+	}
+	for _, p := range unpublished {
+		events = append(events, publicationEvent(entityProject, p.ID, actionUnpublished, "published", "archived", p.UnpublishAt))
+	}
+
+	publishedTestimonials, err := qtx.PublishScheduledTestimonials(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to publish testimonials: %w", err)
+	}
+	for _, t := range publishedTestimonials {
+		events = append(events, publicationEvent(entityTestimonial, t.ID, actionPublished, "pending", "ready", t.PublishAt))
+	}
+
+	unpublishedTestimonials, err := qtx.UnpublishScheduledTestimonials(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to unpublish testimonials: %w", err)
+	}
+	for _, t := range unpublishedTestimonials {
+		events = append(events, publicationEvent(entityTestimonial, t.ID, actionUnpublished, "ready", "pending", t.UnpublishAt))
+	}
+
+	for _, event := range events {
+		if err := qtx.CreatePublicationEvent(ctx, event); err != nil {
+			return fmt.Errorf("failed to record publication event: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for _, event := range events {
+		log.Printf("Publish scheduler: %s %d %s (scheduled for %s)",
+			event.EntityType, event.EntityID, event.Action, event.ScheduledAt.Time.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func publicationEvent(entityType string, id int64, action, from, to string, scheduledAt pgtype.Timestamptz) sqlc.CreatePublicationEventParams {
+	return sqlc.CreatePublicationEventParams{
+		EntityType:  entityType,
+		EntityID:    id,
+		Action:      action,
+		FromStatus:  from,
+		ToStatus:    to,
+		ScheduledAt: scheduledAt,
+	}
+}
@@ -19,40 +19,102 @@ type User struct {
 
 // Project represents a construction project
 type Project struct {
-	ID          int64          `json:"id"`
-	Status      int            `json:"status"`
-	Name        string         `json:"name"`
-	Category    *string        `json:"category,omitempty"`
-	Client      *string        `json:"client,omitempty"`
-	Order       int            `json:"order"`
-	Highlighted bool           `json:"highlighted"`
-	Images      []ProjectImage `json:"images,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	ID           int64   `json:"id"`
+	Status       string  `json:"status"` // draft, published, archived
+	Name         string  `json:"name"`
+	Slug         string  `json:"slug"`
+	CategoryID   *int64  `json:"category_id,omitempty"`
+	Category     *string `json:"category,omitempty"` // category name
+	CategorySlug *string `json:"category_slug,omitempty"`
+	Client       *string `json:"client,omitempty"`
+	Order        int     `json:"order"`
+	Highlighted  bool    `json:"highlighted"`
+	// Details shown on the project page
+	Description    *string        `json:"description,omitempty"`
+	Location       *string        `json:"location,omitempty"` // city or address
+	CompletionYear *int           `json:"completion_year,omitempty"`
+	AreaM2         *float64       `json:"area_m2,omitempty"` // built area in square meters
+	DurationMonths *int           `json:"duration_months,omitempty"`
+	Services       []string       `json:"services"`
+	Images         []ProjectImage `json:"images,omitempty"`
+	// Optional publishing window (see the publishing scheduler)
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// Matching text with the search terms in <mark> tags, set when the list was searched
+	Snippet *string `json:"snippet,omitempty"`
+}
+
+// ProjectOrder is a project's position in the display order (see PUT /api/projects/order)
+type ProjectOrder struct {
+	ID    int64 `json:"id"`
+	Order int   `json:"order"`
+}
+
+// Category represents a project category
+type Category struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Slug         string    `json:"slug"`
+	Order        int       `json:"order"`
+	ProjectCount *int64    `json:"project_count,omitempty"` // set by the list endpoints
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // ProjectImage represents an image associated with a project
 type ProjectImage struct {
-	ID         int64     `json:"id"`
-	Name       string    `json:"name"`
-	URL        string    `json:"url"` // /storage/img/filename.jpg
-	ProjectID  int64     `json:"project_id"`
-	Order      int       `json:"order"`
-	BlurHash   *string   `json:"blur_hash,omitempty"` // data URL
-	Highlighted bool     `json:"highlighted"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	URL         string  `json:"url"` // /storage/img/filename.jpg
+	ProjectID   int64   `json:"project_id"`
+	Order       int     `json:"order"`
+	BlurHash    *string `json:"blur_hash,omitempty"`   // raw blurhash string
+	Placeholder *string `json:"placeholder,omitempty"` // /storage/placeholder/:imageId, the blurhash as a PNG
+	Highlighted bool    `json:"highlighted"`
+	AltText     *string `json:"alt_text,omitempty"` // accessibility text for <img alt>
+	Caption     *string `json:"caption,omitempty"`
+	// ProcessingStatus is "processing" until blurhash, info and variants are generated, then "ready" (or "failed")
+	ProcessingStatus string  `json:"processing_status"`
+	ProcessingError  *string `json:"processing_error,omitempty"` // admin endpoints only
+	// Intrinsic properties (nil for images uploaded before they were recorded)
+	Width         *int           `json:"width,omitempty"`
+	Height        *int           `json:"height,omitempty"`
+	SizeBytes     *int64         `json:"size_bytes,omitempty"`
+	MimeType      *string        `json:"mime_type,omitempty"`
+	DominantColor *string        `json:"dominant_color,omitempty"` // #rrggbb
+	Variants      []ImageVariant `json:"variants,omitempty"`
+	SrcSet        string         `json:"srcset,omitempty"`      // "<url> 320w, <url> 768w, ..."
+	WebPSrcSet    string         `json:"webp_srcset,omitempty"` // same as srcset, WebP copies
+	// Capture metadata extracted from EXIF (opt-in, admin endpoints only)
+	CapturedAt   *time.Time `json:"captured_at,omitempty"`
+	GPSLatitude  *float64   `json:"gps_latitude,omitempty"`
+	GPSLongitude *float64   `json:"gps_longitude,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ImageVariant represents a downscaled copy of a project image
+type ImageVariant struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"` // jpeg, png or webp
+	URL    string `json:"url"`
 }
 
 // Testimonial represents a customer testimonial
 type Testimonial struct {
-	ID          int64     `json:"id"`
-	FullName    string    `json:"full_name"`
-	Profession  string    `json:"profession"`
-	Testimonial string    `json:"testimonial"`
-	Status      string    `json:"status"` // "ready", "pending", etc.
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int64  `json:"id"`
+	FullName    string `json:"full_name"`
+	Profession  string `json:"profession"`
+	Testimonial string `json:"testimonial"`
+	Status      string `json:"status"` // "ready", "pending", etc.
+	// Optional publishing window (see the publishing scheduler)
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 // StaticText represents a static text content item
@@ -85,6 +147,53 @@ type VisitorMessage struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// Upload represents a resumable chunked upload
+type Upload struct {
+	ID        string    `json:"id"`
+	Filename  string    `json:"filename"`
+	Size      int64     `json:"size"`
+	Offset    int64     `json:"offset"` // bytes received so far; the next chunk must start here
+	Status    string    `json:"status"` // uploading, complete
+	SHA1      *string   `json:"sha1,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PublicationEvent is an audit trail entry for a status change made by the publishing scheduler
+type PublicationEvent struct {
+	ID          int64     `json:"id"`
+	EntityType  string    `json:"entity_type"` // project, testimonial
+	EntityID    int64     `json:"entity_id"`
+	Action      string    `json:"action"` // published, unpublished
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SearchHit is a single result of the admin search; ID identifies the entity for a link to it
+type SearchHit struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"` // HTML-escaped, with the matches in <mark> tags
+}
+
+// SearchGroup holds the best hits of one entity type and how many matches there are in total
+type SearchGroup struct {
+	Total int64       `json:"total"`
+	Hits  []SearchHit `json:"hits"`
+}
+
+// SearchResponse represents the results of the admin search, grouped by entity type
+type SearchResponse struct {
+	Query           string      `json:"query"`
+	Projects        SearchGroup `json:"projects"`
+	Testimonials    SearchGroup `json:"testimonials"`
+	VisitorMessages SearchGroup `json:"visitor_messages"`
+	Users           SearchGroup `json:"users"`
+	StaticTexts     SearchGroup `json:"static_texts"`
+}
+
 // PaginationResponse represents a paginated response
 type PaginationResponse struct {
 	Data    interface{} `json:"data"`
@@ -93,6 +202,15 @@ type PaginationResponse struct {
 	Total   int64       `json:"total"`
 }
 
+// CursorPaginationResponse represents a page of a list in cursor mode (?cursor=&limit=).
+// The cursors are opaque; they are null when there is no page in that direction
+type CursorPaginationResponse struct {
+	Data       interface{} `json:"data"`
+	Limit      int         `json:"limit"`
+	NextCursor *string     `json:"next_cursor"`
+	PrevCursor *string     `json:"prev_cursor"`
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error   string      `json:"error"`
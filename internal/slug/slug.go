@@ -0,0 +1,55 @@
+package slug
+
+import (
+	"strings"
+)
+
+// MaxLength is the longest slug Make returns, leaving room for a "-<n>" suffix in VARCHAR(255)
+const MaxLength = 200
+
+// transliterations maps Serbian Latin and Cyrillic letters (lowercase) to ASCII
+var transliterations = map[rune]string{
+	'č': "c", 'ć': "c", 'ž': "z", 'š': "s", 'đ': "dj",
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'ђ': "dj", 'е': "e", 'ж': "z",
+	'з': "z", 'и': "i", 'ј': "j", 'к': "k", 'л': "l", 'љ': "lj", 'м': "m", 'н': "n",
+	'њ': "nj", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'ћ': "c", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "c", 'ч': "c", 'џ': "dz", 'ш': "s",
+	// Other common Latin accents
+	'á': "a", 'à': "a", 'â': "a", 'ä': "a", 'é': "e", 'è': "e", 'ê': "e", 'ë': "e",
+	'í': "i", 'ì': "i", 'î': "i", 'ï': "i", 'ó': "o", 'ò': "o", 'ô': "o", 'ö': "o",
+	'ú': "u", 'ù': "u", 'û': "u", 'ü': "u", 'ã': "a", 'õ': "o", 'ñ': "n", 'ç': "c", 'ý': "y",
+}
+
+// Make turns s into a URL slug: lowercase ASCII letters and digits separated by
+// single dashes, e.g. "Stambeni objekat Čukarica" -> "stambeni-objekat-cukarica".
+// Serbian Latin and Cyrillic letters are transliterated; any other character, including
+// letters outside the table (e.g. "Straße" -> "stra-e"), separates words. This matches the
+// regexp_replace based backfills in the slug and category migrations.
+// It returns "" when nothing usable is left.
+func Make(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		var part string
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			part = string(r)
+		case transliterations[r] != "":
+			part = transliterations[r]
+		default:
+			dash = b.Len() > 0
+			continue
+		}
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteString(part)
+	}
+
+	result := b.String()
+	if len(result) > MaxLength {
+		result = strings.TrimRight(result[:MaxLength], "-")
+	}
+	return result
+}
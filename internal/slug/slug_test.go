@@ -0,0 +1,40 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+// The expected values follow the SQL backfills in migrations 000012 and 000016,
+// which must produce the same slugs as Make
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"serbian latin", "Stambeni objekat Čukarica", "stambeni-objekat-cukarica"},
+		{"serbian latin digraphs", "Đurđevdan ĐAKOVICA", "djurdjevdan-djakovica"},
+		{"serbian cyrillic", "Стамбени објекат Чукарица", "stambeni-objekat-cukarica"},
+		{"cyrillic digraphs", "Љубљана Њива Џеп Ђердап", "ljubljana-njiva-dzep-djerdap"},
+		{"cyrillic uppercase", "ШАБАЦ ЋУПРИЈА", "sabac-cuprija"},
+		{"latin accents", "Café Ñandú Über", "cafe-nandu-uber"},
+		{"unknown letter splits the word", "Straße", "stra-e"},
+		{"unknown script", "Ωmega Tower", "mega-tower"},
+		{"combining mark splits the word", "Cafe\u0301 bar e\u0301x", "cafe-bar-e-x"},
+		{"punctuation and spaces collapse", "  --Hello,   World!-- ", "hello-world"},
+		{"digits", "Zgrada 2023", "zgrada-2023"},
+		{"empty", "", ""},
+		{"nothing usable", "!!! ßß", ""},
+		{"truncated", strings.Repeat("a", MaxLength+50), strings.Repeat("a", MaxLength)},
+		{"truncated without trailing dash", strings.Repeat("a ", MaxLength), strings.Repeat("a-", MaxLength/2-1) + "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Make(tt.in); got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
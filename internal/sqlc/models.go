@@ -8,6 +8,15 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Category struct {
+	ID        int64            `json:"id"`
+	Name      string           `json:"name"`
+	Slug      string           `json:"slug"`
+	Order     int32            `json:"order"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
 type Configuration struct {
 	ID        int64            `json:"id"`
 	Key       string           `json:"key"`
@@ -16,28 +25,94 @@ type Configuration struct {
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
+type ImageJob struct {
+	ID             int64            `json:"id"`
+	ProjectImageID int64            `json:"project_image_id"`
+	Status         string           `json:"status"`
+	Attempts       int32            `json:"attempts"`
+	LastError      pgtype.Text      `json:"last_error"`
+	RunAt          pgtype.Timestamp `json:"run_at"`
+	LockedAt       pgtype.Timestamp `json:"locked_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
 type Project struct {
-	ID          int64            `json:"id"`
-	Status      int16            `json:"status"`
-	Name        string           `json:"name"`
-	Category    pgtype.Text      `json:"category"`
-	Client      pgtype.Text      `json:"client"`
-	Order       int32            `json:"order"`
-	Highlighted bool             `json:"highlighted"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	ID             int64              `json:"id"`
+	Status         string             `json:"status"`
+	Name           string             `json:"name"`
+	Client         pgtype.Text        `json:"client"`
+	Order          int32              `json:"order"`
+	Highlighted    bool               `json:"highlighted"`
+	CreatedAt      pgtype.Timestamp   `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp   `json:"updated_at"`
+	Slug           string             `json:"slug"`
+	PublishAt      pgtype.Timestamptz `json:"publish_at"`
+	UnpublishAt    pgtype.Timestamptz `json:"unpublish_at"`
+	Description    pgtype.Text        `json:"description"`
+	Location       pgtype.Text        `json:"location"`
+	CompletionYear pgtype.Int4        `json:"completion_year"`
+	AreaM2         pgtype.Float8      `json:"area_m2"`
+	DurationMonths pgtype.Int4        `json:"duration_months"`
+	Services       []string           `json:"services"`
+	CategoryID     pgtype.Int8        `json:"category_id"`
 }
 
 type ProjectImage struct {
-	ID          int64            `json:"id"`
-	Name        string           `json:"name"`
-	Url         string           `json:"url"`
-	ProjectID   int64            `json:"project_id"`
-	Order       int32            `json:"order"`
-	BlurHash    pgtype.Text      `json:"blur_hash"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
-	Highlighted bool             `json:"highlighted"`
+	ID               int64            `json:"id"`
+	Name             string           `json:"name"`
+	Url              string           `json:"url"`
+	ProjectID        int64            `json:"project_id"`
+	Order            int32            `json:"order"`
+	BlurHash         pgtype.Text      `json:"blur_hash"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Highlighted      bool             `json:"highlighted"`
+	CapturedAt       pgtype.Timestamp `json:"captured_at"`
+	GpsLatitude      pgtype.Float8    `json:"gps_latitude"`
+	GpsLongitude     pgtype.Float8    `json:"gps_longitude"`
+	Width            pgtype.Int4      `json:"width"`
+	Height           pgtype.Int4      `json:"height"`
+	SizeBytes        pgtype.Int8      `json:"size_bytes"`
+	MimeType         pgtype.Text      `json:"mime_type"`
+	DominantColor    pgtype.Text      `json:"dominant_color"`
+	ProcessingStatus string           `json:"processing_status"`
+	ProcessingError  pgtype.Text      `json:"processing_error"`
+	AltText          pgtype.Text      `json:"alt_text"`
+	Caption          pgtype.Text      `json:"caption"`
+}
+
+type ProjectImageVariant struct {
+	ID             int64            `json:"id"`
+	ProjectImageID int64            `json:"project_image_id"`
+	Width          int32            `json:"width"`
+	Height         int32            `json:"height"`
+	Url            string           `json:"url"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+	Format         string           `json:"format"`
+}
+
+type ProjectSearch struct {
+	ProjectID int64       `json:"project_id"`
+	Document  interface{} `json:"document"`
+}
+
+type ProjectSlugRedirect struct {
+	Slug      string           `json:"slug"`
+	ProjectID int64            `json:"project_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type PublicationEvent struct {
+	ID          int64              `json:"id"`
+	EntityType  string             `json:"entity_type"`
+	EntityID    int64              `json:"entity_id"`
+	Action      string             `json:"action"`
+	FromStatus  string             `json:"from_status"`
+	ToStatus    string             `json:"to_status"`
+	ScheduledAt pgtype.Timestamptz `json:"scheduled_at"`
+	CreatedAt   pgtype.Timestamp   `json:"created_at"`
 }
 
 type StaticText struct {
@@ -49,14 +124,36 @@ type StaticText struct {
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
+type StoredBlob struct {
+	ID        int64            `json:"id"`
+	Url       string           `json:"url"`
+	RefCount  int32            `json:"ref_count"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
 type Testimonial struct {
-	ID          int64            `json:"id"`
-	FullName    string           `json:"full_name"`
-	Profession  string           `json:"profession"`
-	Testimonial string           `json:"testimonial"`
-	Status      string           `json:"status"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	ID          int64              `json:"id"`
+	FullName    string             `json:"full_name"`
+	Profession  string             `json:"profession"`
+	Testimonial string             `json:"testimonial"`
+	Status      string             `json:"status"`
+	CreatedAt   pgtype.Timestamp   `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp   `json:"updated_at"`
+	PublishAt   pgtype.Timestamptz `json:"publish_at"`
+	UnpublishAt pgtype.Timestamptz `json:"unpublish_at"`
+}
+
+type Upload struct {
+	ID        string           `json:"id"`
+	Filename  string           `json:"filename"`
+	Size      int64            `json:"size"`
+	Received  int64            `json:"received"`
+	Sha1      pgtype.Text      `json:"sha1"`
+	Status    string           `json:"status"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
 type User struct {
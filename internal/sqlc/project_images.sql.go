@@ -12,18 +12,27 @@ import (
 )
 
 const createProjectImage = `-- name: CreateProjectImage :one
-INSERT INTO project_images (name, url, project_id, "order", blur_hash, highlighted, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-RETURNING id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted
+INSERT INTO project_images (name, url, project_id, "order", blur_hash, highlighted, captured_at, gps_latitude, gps_longitude, width, height, size_bytes, mime_type, dominant_color, processing_status, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), NOW())
+RETURNING id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted, captured_at, gps_latitude, gps_longitude, width, height, size_bytes, mime_type, dominant_color, processing_status, processing_error, alt_text, caption
 `
 
 type CreateProjectImageParams struct {
-	Name        string      `json:"name"`
-	Url         string      `json:"url"`
-	ProjectID   int64       `json:"project_id"`
-	Order       int32       `json:"order"`
-	BlurHash    pgtype.Text `json:"blur_hash"`
-	Highlighted bool        `json:"highlighted"`
+	Name             string           `json:"name"`
+	Url              string           `json:"url"`
+	ProjectID        int64            `json:"project_id"`
+	Order            int32            `json:"order"`
+	BlurHash         pgtype.Text      `json:"blur_hash"`
+	Highlighted      bool             `json:"highlighted"`
+	CapturedAt       pgtype.Timestamp `json:"captured_at"`
+	GpsLatitude      pgtype.Float8    `json:"gps_latitude"`
+	GpsLongitude     pgtype.Float8    `json:"gps_longitude"`
+	Width            pgtype.Int4      `json:"width"`
+	Height           pgtype.Int4      `json:"height"`
+	SizeBytes        pgtype.Int8      `json:"size_bytes"`
+	MimeType         pgtype.Text      `json:"mime_type"`
+	DominantColor    pgtype.Text      `json:"dominant_color"`
+	ProcessingStatus string           `json:"processing_status"`
 }
 
 func (q *Queries) CreateProjectImage(ctx context.Context, arg CreateProjectImageParams) (ProjectImage, error) {
@@ -34,6 +43,15 @@ func (q *Queries) CreateProjectImage(ctx context.Context, arg CreateProjectImage
 		arg.Order,
 		arg.BlurHash,
 		arg.Highlighted,
+		arg.CapturedAt,
+		arg.GpsLatitude,
+		arg.GpsLongitude,
+		arg.Width,
+		arg.Height,
+		arg.SizeBytes,
+		arg.MimeType,
+		arg.DominantColor,
+		arg.ProcessingStatus,
 	)
 	var i ProjectImage
 	err := row.Scan(
@@ -46,6 +64,18 @@ func (q *Queries) CreateProjectImage(ctx context.Context, arg CreateProjectImage
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Highlighted,
+		&i.CapturedAt,
+		&i.GpsLatitude,
+		&i.GpsLongitude,
+		&i.Width,
+		&i.Height,
+		&i.SizeBytes,
+		&i.MimeType,
+		&i.DominantColor,
+		&i.ProcessingStatus,
+		&i.ProcessingError,
+		&i.AltText,
+		&i.Caption,
 	)
 	return i, err
 }
@@ -60,7 +90,7 @@ func (q *Queries) DeleteProjectImage(ctx context.Context, id int64) error {
 }
 
 const deleteProjectImagesByProjectID = `-- name: DeleteProjectImagesByProjectID :many
-SELECT id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted FROM project_images WHERE project_id = $1
+SELECT id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted, captured_at, gps_latitude, gps_longitude, width, height, size_bytes, mime_type, dominant_color, processing_status, processing_error, alt_text, caption FROM project_images WHERE project_id = $1
 `
 
 func (q *Queries) DeleteProjectImagesByProjectID(ctx context.Context, projectID int64) ([]ProjectImage, error) {
@@ -82,6 +112,18 @@ func (q *Queries) DeleteProjectImagesByProjectID(ctx context.Context, projectID
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Highlighted,
+			&i.CapturedAt,
+			&i.GpsLatitude,
+			&i.GpsLongitude,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.MimeType,
+			&i.DominantColor,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.AltText,
+			&i.Caption,
 		); err != nil {
 			return nil, err
 		}
@@ -94,7 +136,7 @@ func (q *Queries) DeleteProjectImagesByProjectID(ctx context.Context, projectID
 }
 
 const getProjectImageByID = `-- name: GetProjectImageByID :one
-SELECT id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted FROM project_images WHERE id = $1
+SELECT id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted, captured_at, gps_latitude, gps_longitude, width, height, size_bytes, mime_type, dominant_color, processing_status, processing_error, alt_text, caption FROM project_images WHERE id = $1
 `
 
 func (q *Queries) GetProjectImageByID(ctx context.Context, id int64) (ProjectImage, error) {
@@ -110,10 +152,85 @@ func (q *Queries) GetProjectImageByID(ctx context.Context, id int64) (ProjectIma
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Highlighted,
+		&i.CapturedAt,
+		&i.GpsLatitude,
+		&i.GpsLongitude,
+		&i.Width,
+		&i.Height,
+		&i.SizeBytes,
+		&i.MimeType,
+		&i.DominantColor,
+		&i.ProcessingStatus,
+		&i.ProcessingError,
+		&i.AltText,
+		&i.Caption,
 	)
 	return i, err
 }
 
+const getPublishedProjectImageBlurHash = `-- name: GetPublishedProjectImageBlurHash :one
+SELECT pi.blur_hash FROM project_images pi
+JOIN projects p ON p.id = pi.project_id
+WHERE pi.id = $1
+  AND p.status = 'published'
+  AND (p.publish_at IS NULL OR p.publish_at <= NOW())
+  AND (p.unpublish_at IS NULL OR p.unpublish_at > NOW())
+`
+
+// Blurhash of an image whose project is publicly visible
+func (q *Queries) GetPublishedProjectImageBlurHash(ctx context.Context, id int64) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getPublishedProjectImageBlurHash, id)
+	var blur_hash pgtype.Text
+	err := row.Scan(&blur_hash)
+	return blur_hash, err
+}
+
+const listAllProjectImages = `-- name: ListAllProjectImages :many
+SELECT id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted, captured_at, gps_latitude, gps_longitude, width, height, size_bytes, mime_type, dominant_color, processing_status, processing_error, alt_text, caption FROM project_images ORDER BY id ASC
+`
+
+func (q *Queries) ListAllProjectImages(ctx context.Context) ([]ProjectImage, error) {
+	rows, err := q.db.Query(ctx, listAllProjectImages)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectImage
+	for rows.Next() {
+		var i ProjectImage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.ProjectID,
+			&i.Order,
+			&i.BlurHash,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Highlighted,
+			&i.CapturedAt,
+			&i.GpsLatitude,
+			&i.GpsLongitude,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.MimeType,
+			&i.DominantColor,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.AltText,
+			&i.Caption,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProjectImageIDsByProjectID = `-- name: ListProjectImageIDsByProjectID :many
 SELECT id FROM project_images WHERE project_id = $1
 `
@@ -139,7 +256,7 @@ func (q *Queries) ListProjectImageIDsByProjectID(ctx context.Context, projectID
 }
 
 const listProjectImagesByProjectID = `-- name: ListProjectImagesByProjectID :many
-SELECT id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted FROM project_images WHERE project_id = $1 ORDER BY "order" ASC
+SELECT id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted, captured_at, gps_latitude, gps_longitude, width, height, size_bytes, mime_type, dominant_color, processing_status, processing_error, alt_text, caption FROM project_images WHERE project_id = $1 ORDER BY "order" ASC
 `
 
 func (q *Queries) ListProjectImagesByProjectID(ctx context.Context, projectID int64) ([]ProjectImage, error) {
@@ -161,6 +278,113 @@ func (q *Queries) ListProjectImagesByProjectID(ctx context.Context, projectID in
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Highlighted,
+			&i.CapturedAt,
+			&i.GpsLatitude,
+			&i.GpsLongitude,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.MimeType,
+			&i.DominantColor,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.AltText,
+			&i.Caption,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectImagesByProjectIDs = `-- name: ListProjectImagesByProjectIDs :many
+SELECT id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted, captured_at, gps_latitude, gps_longitude, width, height, size_bytes, mime_type, dominant_color, processing_status, processing_error, alt_text, caption FROM project_images WHERE project_id = ANY($1::bigint[]) ORDER BY project_id ASC, "order" ASC
+`
+
+func (q *Queries) ListProjectImagesByProjectIDs(ctx context.Context, dollar_1 []int64) ([]ProjectImage, error) {
+	rows, err := q.db.Query(ctx, listProjectImagesByProjectIDs, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectImage
+	for rows.Next() {
+		var i ProjectImage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.ProjectID,
+			&i.Order,
+			&i.BlurHash,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Highlighted,
+			&i.CapturedAt,
+			&i.GpsLatitude,
+			&i.GpsLongitude,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.MimeType,
+			&i.DominantColor,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.AltText,
+			&i.Caption,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectImagesMissingInfo = `-- name: ListProjectImagesMissingInfo :many
+SELECT id, name, url, project_id, "order", blur_hash, created_at, updated_at, highlighted, captured_at, gps_latitude, gps_longitude, width, height, size_bytes, mime_type, dominant_color, processing_status, processing_error, alt_text, caption FROM project_images
+WHERE (width IS NULL OR height IS NULL OR size_bytes IS NULL OR mime_type IS NULL OR dominant_color IS NULL OR blur_hash IS NULL)
+  AND processing_status <> 'processing'
+ORDER BY id ASC
+`
+
+func (q *Queries) ListProjectImagesMissingInfo(ctx context.Context) ([]ProjectImage, error) {
+	rows, err := q.db.Query(ctx, listProjectImagesMissingInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectImage
+	for rows.Next() {
+		var i ProjectImage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.ProjectID,
+			&i.Order,
+			&i.BlurHash,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Highlighted,
+			&i.CapturedAt,
+			&i.GpsLatitude,
+			&i.GpsLongitude,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.MimeType,
+			&i.DominantColor,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.AltText,
+			&i.Caption,
 		); err != nil {
 			return nil, err
 		}
@@ -172,6 +396,32 @@ func (q *Queries) ListProjectImagesByProjectID(ctx context.Context, projectID in
 	return items, nil
 }
 
+const listReferencedStorageURLs = `-- name: ListReferencedStorageURLs :many
+SELECT url FROM project_images
+UNION
+SELECT url FROM project_image_variants
+`
+
+func (q *Queries) ListReferencedStorageURLs(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listReferencedStorageURLs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		items = append(items, url)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const unhighlightAllProjectImages = `-- name: UnhighlightAllProjectImages :exec
 UPDATE project_images
 SET highlighted = false,
@@ -215,3 +465,81 @@ func (q *Queries) UpdateProjectImage(ctx context.Context, arg UpdateProjectImage
 	)
 	return err
 }
+
+const updateProjectImageDetails = `-- name: UpdateProjectImageDetails :exec
+UPDATE project_images
+SET name = $2,
+    alt_text = $3,
+    caption = $4,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateProjectImageDetailsParams struct {
+	ID      int64       `json:"id"`
+	Name    string      `json:"name"`
+	AltText pgtype.Text `json:"alt_text"`
+	Caption pgtype.Text `json:"caption"`
+}
+
+func (q *Queries) UpdateProjectImageDetails(ctx context.Context, arg UpdateProjectImageDetailsParams) error {
+	_, err := q.db.Exec(ctx, updateProjectImageDetails,
+		arg.ID,
+		arg.Name,
+		arg.AltText,
+		arg.Caption,
+	)
+	return err
+}
+
+const updateProjectImageInfo = `-- name: UpdateProjectImageInfo :exec
+UPDATE project_images
+SET width = $2,
+    height = $3,
+    size_bytes = $4,
+    mime_type = $5,
+    dominant_color = $6,
+    blur_hash = COALESCE(blur_hash, $7)
+WHERE id = $1
+`
+
+type UpdateProjectImageInfoParams struct {
+	ID            int64       `json:"id"`
+	Width         pgtype.Int4 `json:"width"`
+	Height        pgtype.Int4 `json:"height"`
+	SizeBytes     pgtype.Int8 `json:"size_bytes"`
+	MimeType      pgtype.Text `json:"mime_type"`
+	DominantColor pgtype.Text `json:"dominant_color"`
+	BlurHash      pgtype.Text `json:"blur_hash"`
+}
+
+func (q *Queries) UpdateProjectImageInfo(ctx context.Context, arg UpdateProjectImageInfoParams) error {
+	_, err := q.db.Exec(ctx, updateProjectImageInfo,
+		arg.ID,
+		arg.Width,
+		arg.Height,
+		arg.SizeBytes,
+		arg.MimeType,
+		arg.DominantColor,
+		arg.BlurHash,
+	)
+	return err
+}
+
+const updateProjectImageProcessingStatus = `-- name: UpdateProjectImageProcessingStatus :exec
+UPDATE project_images
+SET processing_status = $2,
+    processing_error = $3
+WHERE id = $1
+`
+
+type UpdateProjectImageProcessingStatusParams struct {
+	ID               int64       `json:"id"`
+	ProcessingStatus string      `json:"processing_status"`
+	ProcessingError  pgtype.Text `json:"processing_error"`
+}
+
+func (q *Queries) UpdateProjectImageProcessingStatus(ctx context.Context, arg UpdateProjectImageProcessingStatusParams) error {
+	_, err := q.db.Exec(ctx, updateProjectImageProcessingStatus, arg.ID, arg.ProcessingStatus, arg.ProcessingError)
+	return err
+}
@@ -23,51 +23,131 @@ func (q *Queries) CountProjects(ctx context.Context) (int64, error) {
 }
 
 const countProjectsWithSearch = `-- name: CountProjectsWithSearch :one
-SELECT COUNT(*) FROM projects WHERE ($1::text IS NULL OR $1::text = '' OR name ILIKE '%' || $1 || '%')
+SELECT COUNT(*) FROM projects
+WHERE ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($2::text = '' OR status = $2::text)
+  AND ($3::text = '' OR location ILIKE '%' || $3::text || '%')
+  AND ($4::int = 0 OR completion_year = $4::int)
+  AND ($5::text = '' OR $5::text = ANY(services))
+`
+
+type CountProjectsWithSearchParams struct {
+	Column1 string `json:"column_1"`
+	Column2 string `json:"column_2"`
+	Column3 string `json:"column_3"`
+	Column4 int32  `json:"column_4"`
+	Column5 string `json:"column_5"`
+}
+
+func (q *Queries) CountProjectsWithSearch(ctx context.Context, arg CountProjectsWithSearchParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countProjectsWithSearch,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPublishedProjects = `-- name: CountPublishedProjects :one
+SELECT COUNT(*) FROM projects
+WHERE status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+  AND ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($2::text = '' OR EXISTS (SELECT 1 FROM categories WHERE categories.id = projects.category_id AND categories.slug = $2::text))
+  AND ($3::text = '' OR client ILIKE '%' || $3::text || '%')
+  AND ($4::int = 0 OR completion_year = $4::int)
+  AND ($5::text = '' OR highlighted = ($5::text = 'true'))
 `
 
-func (q *Queries) CountProjectsWithSearch(ctx context.Context, dollar_1 string) (int64, error) {
-	row := q.db.QueryRow(ctx, countProjectsWithSearch, dollar_1)
+type CountPublishedProjectsParams struct {
+	Column1 string `json:"column_1"`
+	Column2 string `json:"column_2"`
+	Column3 string `json:"column_3"`
+	Column4 int32  `json:"column_4"`
+	Column5 string `json:"column_5"`
+}
+
+// Same filters as ListPublicProjects
+func (q *Queries) CountPublishedProjects(ctx context.Context, arg CountPublishedProjectsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPublishedProjects,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const createProject = `-- name: CreateProject :one
-INSERT INTO projects (status, name, category, client, "order", highlighted, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-RETURNING id, status, name, category, client, "order", highlighted, created_at, updated_at
+INSERT INTO projects (status, name, category_id, client, "order", highlighted, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), NOW())
+RETURNING id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id
 `
 
 type CreateProjectParams struct {
-	Status      int16       `json:"status"`
-	Name        string      `json:"name"`
-	Category    pgtype.Text `json:"category"`
-	Client      pgtype.Text `json:"client"`
-	Order       int32       `json:"order"`
-	Highlighted bool        `json:"highlighted"`
+	Status         string             `json:"status"`
+	Name           string             `json:"name"`
+	CategoryID     pgtype.Int8        `json:"category_id"`
+	Client         pgtype.Text        `json:"client"`
+	Order          int32              `json:"order"`
+	Highlighted    bool               `json:"highlighted"`
+	Slug           string             `json:"slug"`
+	PublishAt      pgtype.Timestamptz `json:"publish_at"`
+	UnpublishAt    pgtype.Timestamptz `json:"unpublish_at"`
+	Description    pgtype.Text        `json:"description"`
+	Location       pgtype.Text        `json:"location"`
+	CompletionYear pgtype.Int4        `json:"completion_year"`
+	AreaM2         pgtype.Float8      `json:"area_m2"`
+	DurationMonths pgtype.Int4        `json:"duration_months"`
+	Services       []string           `json:"services"`
 }
 
 func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error) {
 	row := q.db.QueryRow(ctx, createProject,
 		arg.Status,
 		arg.Name,
-		arg.Category,
+		arg.CategoryID,
 		arg.Client,
 		arg.Order,
 		arg.Highlighted,
+		arg.Slug,
+		arg.PublishAt,
+		arg.UnpublishAt,
+		arg.Description,
+		arg.Location,
+		arg.CompletionYear,
+		arg.AreaM2,
+		arg.DurationMonths,
+		arg.Services,
 	)
 	var i Project
 	err := row.Scan(
 		&i.ID,
 		&i.Status,
 		&i.Name,
-		&i.Category,
 		&i.Client,
 		&i.Order,
 		&i.Highlighted,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Slug,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Description,
+		&i.Location,
+		&i.CompletionYear,
+		&i.AreaM2,
+		&i.DurationMonths,
+		&i.Services,
+		&i.CategoryID,
 	)
 	return i, err
 }
@@ -82,7 +162,7 @@ func (q *Queries) DeleteProject(ctx context.Context, id int64) error {
 }
 
 const getProjectByID = `-- name: GetProjectByID :one
-SELECT id, status, name, category, client, "order", highlighted, created_at, updated_at FROM projects WHERE id = $1
+SELECT id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id FROM projects WHERE id = $1
 `
 
 func (q *Queries) GetProjectByID(ctx context.Context, id int64) (Project, error) {
@@ -92,18 +172,100 @@ func (q *Queries) GetProjectByID(ctx context.Context, id int64) (Project, error)
 		&i.ID,
 		&i.Status,
 		&i.Name,
-		&i.Category,
 		&i.Client,
 		&i.Order,
 		&i.Highlighted,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Slug,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Description,
+		&i.Location,
+		&i.CompletionYear,
+		&i.AreaM2,
+		&i.DurationMonths,
+		&i.Services,
+		&i.CategoryID,
+	)
+	return i, err
+}
+
+const getPublishedProjectByID = `-- name: GetPublishedProjectByID :one
+SELECT id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id FROM projects
+WHERE id = $1
+  AND status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+`
+
+func (q *Queries) GetPublishedProjectByID(ctx context.Context, id int64) (Project, error) {
+	row := q.db.QueryRow(ctx, getPublishedProjectByID, id)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.Name,
+		&i.Client,
+		&i.Order,
+		&i.Highlighted,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Slug,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Description,
+		&i.Location,
+		&i.CompletionYear,
+		&i.AreaM2,
+		&i.DurationMonths,
+		&i.Services,
+		&i.CategoryID,
+	)
+	return i, err
+}
+
+const getPublishedProjectBySlug = `-- name: GetPublishedProjectBySlug :one
+SELECT id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id FROM projects
+WHERE slug = $1
+  AND status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+`
+
+func (q *Queries) GetPublishedProjectBySlug(ctx context.Context, slug string) (Project, error) {
+	row := q.db.QueryRow(ctx, getPublishedProjectBySlug, slug)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.Name,
+		&i.Client,
+		&i.Order,
+		&i.Highlighted,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Slug,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Description,
+		&i.Location,
+		&i.CompletionYear,
+		&i.AreaM2,
+		&i.DurationMonths,
+		&i.Services,
+		&i.CategoryID,
 	)
 	return i, err
 }
 
 const listHighlightedProjects = `-- name: ListHighlightedProjects :many
-SELECT id, status, name, category, client, "order", highlighted, created_at, updated_at FROM projects WHERE highlighted = true ORDER BY "order" ASC, created_at DESC
+SELECT id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id FROM projects
+WHERE highlighted = true
+  AND status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+ORDER BY "order" ASC, created_at DESC
 `
 
 func (q *Queries) ListHighlightedProjects(ctx context.Context) ([]Project, error) {
@@ -119,12 +281,21 @@ func (q *Queries) ListHighlightedProjects(ctx context.Context) ([]Project, error
 			&i.ID,
 			&i.Status,
 			&i.Name,
-			&i.Category,
 			&i.Client,
 			&i.Order,
 			&i.Highlighted,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Slug,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Description,
+			&i.Location,
+			&i.CompletionYear,
+			&i.AreaM2,
+			&i.DurationMonths,
+			&i.Services,
+			&i.CategoryID,
 		); err != nil {
 			return nil, err
 		}
@@ -136,8 +307,77 @@ func (q *Queries) ListHighlightedProjects(ctx context.Context) ([]Project, error
 	return items, nil
 }
 
+const listProjectIDsByOrder = `-- name: ListProjectIDsByOrder :many
+SELECT id FROM projects ORDER BY "order" ASC, created_at DESC, id ASC FOR UPDATE
+`
+
+// All project IDs in display order (as in the admin list). Locks the rows,
+// so that concurrent reorders apply one after the other
+func (q *Queries) ListProjectIDsByOrder(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listProjectIDsByOrder)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectSearchSnippets = `-- name: ListProjectSearchSnippets :many
+SELECT p.id AS project_id,
+    ts_headline('unaccent_simple',
+        concat_ws(' · ', p.name, p.client, c.name, p.location, array_to_string(p.services, ', '), p.description,
+            (SELECT string_agg(i.caption, ' · ' ORDER BY i."order") FROM project_images i WHERE i.project_id = p.id)),
+        to_tsquery('unaccent_simple', $2::text),
+        'StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=25, MaxFragments=2, FragmentDelimiter=" … "')::text AS snippet
+FROM projects p
+LEFT JOIN categories c ON c.id = p.category_id
+WHERE p.id = ANY($1::bigint[])
+`
+
+type ListProjectSearchSnippetsParams struct {
+	Column1 []int64 `json:"column_1"`
+	Column2 string  `json:"column_2"`
+}
+
+type ListProjectSearchSnippetsRow struct {
+	ProjectID int64  `json:"project_id"`
+	Snippet   string `json:"snippet"`
+}
+
+// Fragments of the given projects' text matching the tsquery $2, with the matches in <mark> tags
+func (q *Queries) ListProjectSearchSnippets(ctx context.Context, arg ListProjectSearchSnippetsParams) ([]ListProjectSearchSnippetsRow, error) {
+	rows, err := q.db.Query(ctx, listProjectSearchSnippets, arg.Column1, arg.Column2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProjectSearchSnippetsRow
+	for rows.Next() {
+		var i ListProjectSearchSnippetsRow
+		if err := rows.Scan(&i.ProjectID, &i.Snippet); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProjects = `-- name: ListProjects :many
-SELECT id, status, name, category, client, "order", highlighted, created_at, updated_at FROM projects ORDER BY "order" ASC, created_at DESC LIMIT $1 OFFSET $2
+SELECT id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id FROM projects ORDER BY "order" ASC, created_at DESC LIMIT $1 OFFSET $2
 `
 
 type ListProjectsParams struct {
@@ -158,12 +398,115 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 			&i.ID,
 			&i.Status,
 			&i.Name,
-			&i.Category,
 			&i.Client,
 			&i.Order,
 			&i.Highlighted,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Slug,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Description,
+			&i.Location,
+			&i.CompletionYear,
+			&i.AreaM2,
+			&i.DurationMonths,
+			&i.Services,
+			&i.CategoryID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectsKeyset = `-- name: ListProjectsKeyset :many
+SELECT id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id FROM projects
+WHERE ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($2::text = '' OR status = $2::text)
+  AND ($3::text = '' OR location ILIKE '%' || $3::text || '%')
+  AND ($4::int = 0 OR completion_year = $4::int)
+  AND ($5::text = '' OR $5::text = ANY(services))
+  AND ($11::bigint = 0 OR CASE $6::text
+    WHEN 'name' THEN CASE WHEN $7::boolean THEN (name, id) < ($9::text, $11::bigint) ELSE (name, id) > ($9::text, $11::bigint) END
+    WHEN 'created_at' THEN CASE WHEN $7::boolean THEN (created_at, id) < ($10::timestamp, $11::bigint) ELSE (created_at, id) > ($10::timestamp, $11::bigint) END
+    ELSE CASE WHEN $7::boolean THEN ("order", id) < ($8::int, $11::bigint) ELSE ("order", id) > ($8::int, $11::bigint) END
+  END)
+ORDER BY
+  CASE WHEN $6::text = 'name' AND NOT $7::boolean THEN name END ASC,
+  CASE WHEN $6::text = 'name' AND $7::boolean THEN name END DESC,
+  CASE WHEN $6::text = 'created_at' AND NOT $7::boolean THEN created_at END ASC,
+  CASE WHEN $6::text = 'created_at' AND $7::boolean THEN created_at END DESC,
+  CASE WHEN $6::text NOT IN ('name', 'created_at') AND NOT $7::boolean THEN "order" END ASC,
+  CASE WHEN $6::text NOT IN ('name', 'created_at') AND $7::boolean THEN "order" END DESC,
+  CASE WHEN NOT $7::boolean THEN id END ASC,
+  CASE WHEN $7::boolean THEN id END DESC
+LIMIT $12
+`
+
+type ListProjectsKeysetParams struct {
+	Column1  string           `json:"column_1"`
+	Column2  string           `json:"column_2"`
+	Column3  string           `json:"column_3"`
+	Column4  int32            `json:"column_4"`
+	Column5  string           `json:"column_5"`
+	Column6  string           `json:"column_6"`
+	Column7  bool             `json:"column_7"`
+	Column8  int32            `json:"column_8"`
+	Column9  string           `json:"column_9"`
+	Column10 pgtype.Timestamp `json:"column_10"`
+	Column11 int64            `json:"column_11"`
+	Limit    int32            `json:"limit"`
+}
+
+// Keyset pagination of ListProjectsWithSearch (same filters $1-$5), ordered by $6 (order, name
+// or created_at) and id, descending when $7 is true. With a cursor ($11 > 0), only the rows after
+// its sort value ($8 order, $9 name, $10 created_at) and id are returned.
+func (q *Queries) ListProjectsKeyset(ctx context.Context, arg ListProjectsKeysetParams) ([]Project, error) {
+	rows, err := q.db.Query(ctx, listProjectsKeyset,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+		arg.Column10,
+		arg.Column11,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.Name,
+			&i.Client,
+			&i.Order,
+			&i.Highlighted,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Slug,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Description,
+			&i.Location,
+			&i.CompletionYear,
+			&i.AreaM2,
+			&i.DurationMonths,
+			&i.Services,
+			&i.CategoryID,
 		); err != nil {
 			return nil, err
 		}
@@ -176,8 +519,12 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 }
 
 const listProjectsWithSearch = `-- name: ListProjectsWithSearch :many
-SELECT id, status, name, category, client, "order", highlighted, created_at, updated_at FROM projects 
-WHERE ($1::text IS NULL OR $1::text = '' OR name ILIKE '%' || $1 || '%')
+SELECT id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id FROM projects
+WHERE ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($6::text = '' OR status = $6::text)
+  AND ($7::text = '' OR location ILIKE '%' || $7::text || '%')
+  AND ($8::int = 0 OR completion_year = $8::int)
+  AND ($9::text = '' OR $9::text = ANY(services))
 ORDER BY 
   CASE WHEN $2::text = 'order' AND $3::text = 'asc' THEN "order" ELSE NULL END ASC,
   CASE WHEN $2::text = 'order' AND $3::text = 'desc' THEN "order" ELSE NULL END DESC,
@@ -185,6 +532,7 @@ ORDER BY
   CASE WHEN $2::text = 'name' AND $3::text = 'desc' THEN name ELSE NULL END DESC,
   CASE WHEN $2::text = 'created_at' AND $3::text = 'asc' THEN created_at ELSE NULL END ASC,
   CASE WHEN $2::text = 'created_at' AND $3::text = 'desc' THEN created_at ELSE NULL END DESC,
+  CASE WHEN $1::text <> '' AND $2::text NOT IN ('order', 'name', 'created_at') THEN (SELECT ts_rank(document, to_tsquery('unaccent_simple', $1::text)) FROM project_search WHERE project_id = projects.id) ELSE NULL END DESC NULLS LAST,
   CASE WHEN $2::text IS NULL OR $2::text = '' OR $2::text NOT IN ('order', 'name', 'created_at') THEN "order" ELSE NULL END ASC,
   created_at DESC
 LIMIT $4 OFFSET $5
@@ -196,8 +544,13 @@ type ListProjectsWithSearchParams struct {
 	Column3 string `json:"column_3"`
 	Limit   int32  `json:"limit"`
 	Offset  int32  `json:"offset"`
+	Column6 string `json:"column_6"`
+	Column7 string `json:"column_7"`
+	Column8 int32  `json:"column_8"`
+	Column9 string `json:"column_9"`
 }
 
+// Search matches name, client, category, description, location and services
 func (q *Queries) ListProjectsWithSearch(ctx context.Context, arg ListProjectsWithSearchParams) ([]Project, error) {
 	rows, err := q.db.Query(ctx, listProjectsWithSearch,
 		arg.Column1,
@@ -205,6 +558,10 @@ func (q *Queries) ListProjectsWithSearch(ctx context.Context, arg ListProjectsWi
 		arg.Column3,
 		arg.Limit,
 		arg.Offset,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
 	)
 	if err != nil {
 		return nil, err
@@ -217,12 +574,21 @@ func (q *Queries) ListProjectsWithSearch(ctx context.Context, arg ListProjectsWi
 			&i.ID,
 			&i.Status,
 			&i.Name,
-			&i.Category,
 			&i.Client,
 			&i.Order,
 			&i.Highlighted,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Slug,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Description,
+			&i.Location,
+			&i.CompletionYear,
+			&i.AreaM2,
+			&i.DurationMonths,
+			&i.Services,
+			&i.CategoryID,
 		); err != nil {
 			return nil, err
 		}
@@ -235,11 +601,57 @@ func (q *Queries) ListProjectsWithSearch(ctx context.Context, arg ListProjectsWi
 }
 
 const listPublicProjects = `-- name: ListPublicProjects :many
-SELECT id, status, name, category, client, "order", highlighted, created_at, updated_at FROM projects ORDER BY "order" ASC, created_at DESC
+SELECT id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id FROM projects
+WHERE status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+  AND ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($2::text = '' OR EXISTS (SELECT 1 FROM categories WHERE categories.id = projects.category_id AND categories.slug = $2::text))
+  AND ($3::text = '' OR client ILIKE '%' || $3::text || '%')
+  AND ($4::int = 0 OR completion_year = $4::int)
+  AND ($5::text = '' OR highlighted = ($5::text = 'true'))
+ORDER BY
+  CASE WHEN $6::text = 'order' AND $7::text = 'asc' THEN "order" ELSE NULL END ASC,
+  CASE WHEN $6::text = 'order' AND $7::text = 'desc' THEN "order" ELSE NULL END DESC,
+  CASE WHEN $6::text = 'name' AND $7::text = 'asc' THEN name ELSE NULL END ASC,
+  CASE WHEN $6::text = 'name' AND $7::text = 'desc' THEN name ELSE NULL END DESC,
+  CASE WHEN $6::text = 'created_at' AND $7::text = 'asc' THEN created_at ELSE NULL END ASC,
+  CASE WHEN $6::text = 'created_at' AND $7::text = 'desc' THEN created_at ELSE NULL END DESC,
+  CASE WHEN $6::text = 'completion_year' AND $7::text = 'asc' THEN completion_year ELSE NULL END ASC NULLS LAST,
+  CASE WHEN $6::text = 'completion_year' AND $7::text = 'desc' THEN completion_year ELSE NULL END DESC NULLS LAST,
+  CASE WHEN $1::text <> '' AND $6::text NOT IN ('order', 'name', 'created_at', 'completion_year') THEN (SELECT ts_rank(document, to_tsquery('unaccent_simple', $1::text)) FROM project_search WHERE project_id = projects.id) ELSE NULL END DESC NULLS LAST,
+  CASE WHEN $6::text NOT IN ('order', 'name', 'created_at', 'completion_year') THEN "order" ELSE NULL END ASC,
+  created_at DESC
+LIMIT NULLIF($8::int, 0) OFFSET $9::int
 `
 
-func (q *Queries) ListPublicProjects(ctx context.Context) ([]Project, error) {
-	rows, err := q.db.Query(ctx, listPublicProjects)
+type ListPublicProjectsParams struct {
+	Column1 string `json:"column_1"`
+	Column2 string `json:"column_2"`
+	Column3 string `json:"column_3"`
+	Column4 int32  `json:"column_4"`
+	Column5 string `json:"column_5"`
+	Column6 string `json:"column_6"`
+	Column7 string `json:"column_7"`
+	Column8 int32  `json:"column_8"`
+	Column9 int32  `json:"column_9"`
+}
+
+// Filters: $1 search (as in ListProjectsWithSearch), $2 category slug, $3 client,
+// $4 completion year, $5 highlighted ('true'/'false'); empty/0 means no filter.
+// A limit of 0 returns all matching projects.
+func (q *Queries) ListPublicProjects(ctx context.Context, arg ListPublicProjectsParams) ([]Project, error) {
+	rows, err := q.db.Query(ctx, listPublicProjects,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -251,12 +663,21 @@ func (q *Queries) ListPublicProjects(ctx context.Context) ([]Project, error) {
 			&i.ID,
 			&i.Status,
 			&i.Name,
-			&i.Category,
 			&i.Client,
 			&i.Order,
 			&i.Highlighted,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Slug,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Description,
+			&i.Location,
+			&i.CompletionYear,
+			&i.AreaM2,
+			&i.DurationMonths,
+			&i.Services,
+			&i.CategoryID,
 		); err != nil {
 			return nil, err
 		}
@@ -268,17 +689,70 @@ func (q *Queries) ListPublicProjects(ctx context.Context) ([]Project, error) {
 	return items, nil
 }
 
-const listPublicProjectsPaginated = `-- name: ListPublicProjectsPaginated :many
-SELECT id, status, name, category, client, "order", highlighted, created_at, updated_at FROM projects ORDER BY "order" ASC, created_at DESC LIMIT $1 OFFSET $2
+const listPublicProjectsKeyset = `-- name: ListPublicProjectsKeyset :many
+SELECT id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id FROM projects
+WHERE status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+  AND ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($2::text = '' OR EXISTS (SELECT 1 FROM categories WHERE categories.id = projects.category_id AND categories.slug = $2::text))
+  AND ($3::text = '' OR client ILIKE '%' || $3::text || '%')
+  AND ($4::int = 0 OR completion_year = $4::int)
+  AND ($5::text = '' OR highlighted = ($5::text = 'true'))
+  AND ($11::bigint = 0 OR CASE $6::text
+    WHEN 'name' THEN CASE WHEN $7::boolean THEN (name, id) < ($9::text, $11::bigint) ELSE (name, id) > ($9::text, $11::bigint) END
+    WHEN 'created_at' THEN CASE WHEN $7::boolean THEN (created_at, id) < ($10::timestamp, $11::bigint) ELSE (created_at, id) > ($10::timestamp, $11::bigint) END
+    WHEN 'completion_year' THEN CASE WHEN $7::boolean THEN (COALESCE(completion_year, 0), id) < ($8::int, $11::bigint) ELSE (COALESCE(completion_year, 0), id) > ($8::int, $11::bigint) END
+    ELSE CASE WHEN $7::boolean THEN ("order", id) < ($8::int, $11::bigint) ELSE ("order", id) > ($8::int, $11::bigint) END
+  END)
+ORDER BY
+  CASE WHEN $6::text = 'name' AND NOT $7::boolean THEN name END ASC,
+  CASE WHEN $6::text = 'name' AND $7::boolean THEN name END DESC,
+  CASE WHEN $6::text = 'created_at' AND NOT $7::boolean THEN created_at END ASC,
+  CASE WHEN $6::text = 'created_at' AND $7::boolean THEN created_at END DESC,
+  CASE WHEN $6::text = 'completion_year' AND NOT $7::boolean THEN COALESCE(completion_year, 0) END ASC,
+  CASE WHEN $6::text = 'completion_year' AND $7::boolean THEN COALESCE(completion_year, 0) END DESC,
+  CASE WHEN $6::text NOT IN ('name', 'created_at', 'completion_year') AND NOT $7::boolean THEN "order" END ASC,
+  CASE WHEN $6::text NOT IN ('name', 'created_at', 'completion_year') AND $7::boolean THEN "order" END DESC,
+  CASE WHEN NOT $7::boolean THEN id END ASC,
+  CASE WHEN $7::boolean THEN id END DESC
+LIMIT $12
 `
 
-type ListPublicProjectsPaginatedParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+type ListPublicProjectsKeysetParams struct {
+	Column1  string           `json:"column_1"`
+	Column2  string           `json:"column_2"`
+	Column3  string           `json:"column_3"`
+	Column4  int32            `json:"column_4"`
+	Column5  string           `json:"column_5"`
+	Column6  string           `json:"column_6"`
+	Column7  bool             `json:"column_7"`
+	Column8  int32            `json:"column_8"`
+	Column9  string           `json:"column_9"`
+	Column10 pgtype.Timestamp `json:"column_10"`
+	Column11 int64            `json:"column_11"`
+	Limit    int32            `json:"limit"`
 }
 
-func (q *Queries) ListPublicProjectsPaginated(ctx context.Context, arg ListPublicProjectsPaginatedParams) ([]Project, error) {
-	rows, err := q.db.Query(ctx, listPublicProjectsPaginated, arg.Limit, arg.Offset)
+// Keyset pagination of ListPublicProjects (same filters $1-$5), ordered by $6 (order, name,
+// created_at or completion_year, missing years as 0) and id, descending when $7 is true.
+// With a cursor ($11 > 0), only the rows after its sort value ($8 order or completion year,
+// $9 name, $10 created_at) and id are returned.
+func (q *Queries) ListPublicProjectsKeyset(ctx context.Context, arg ListPublicProjectsKeysetParams) ([]Project, error) {
+	rows, err := q.db.Query(ctx, listPublicProjectsKeyset,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+		arg.Column10,
+		arg.Column11,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -290,12 +764,21 @@ func (q *Queries) ListPublicProjectsPaginated(ctx context.Context, arg ListPubli
 			&i.ID,
 			&i.Status,
 			&i.Name,
-			&i.Category,
 			&i.Client,
 			&i.Order,
 			&i.Highlighted,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Slug,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Description,
+			&i.Location,
+			&i.CompletionYear,
+			&i.AreaM2,
+			&i.DurationMonths,
+			&i.Services,
+			&i.CategoryID,
 		); err != nil {
 			return nil, err
 		}
@@ -307,6 +790,93 @@ func (q *Queries) ListPublicProjectsPaginated(ctx context.Context, arg ListPubli
 	return items, nil
 }
 
+const moveProjectsToCategory = `-- name: MoveProjectsToCategory :exec
+UPDATE projects
+SET category_id = $2,
+    updated_at = NOW()
+WHERE category_id = $1
+`
+
+type MoveProjectsToCategoryParams struct {
+	CategoryID   pgtype.Int8 `json:"category_id"`
+	CategoryID_2 pgtype.Int8 `json:"category_id_2"`
+}
+
+func (q *Queries) MoveProjectsToCategory(ctx context.Context, arg MoveProjectsToCategoryParams) error {
+	_, err := q.db.Exec(ctx, moveProjectsToCategory, arg.CategoryID, arg.CategoryID_2)
+	return err
+}
+
+const projectSlugTaken = `-- name: ProjectSlugTaken :one
+SELECT (
+  EXISTS (SELECT 1 FROM projects WHERE projects.slug = $1 AND projects.id <> $2)
+  OR EXISTS (SELECT 1 FROM project_slug_redirects WHERE project_slug_redirects.slug = $1 AND project_slug_redirects.project_id <> $2)
+)::boolean AS taken
+`
+
+type ProjectSlugTakenParams struct {
+	Slug string `json:"slug"`
+	ID   int64  `json:"id"`
+}
+
+// Slugs of other projects, current or redirected, cannot be reused
+func (q *Queries) ProjectSlugTaken(ctx context.Context, arg ProjectSlugTakenParams) (bool, error) {
+	row := q.db.QueryRow(ctx, projectSlugTaken, arg.Slug, arg.ID)
+	var taken bool
+	err := row.Scan(&taken)
+	return taken, err
+}
+
+const publishScheduledProjects = `-- name: PublishScheduledProjects :many
+UPDATE projects
+SET status = 'published',
+    updated_at = NOW()
+WHERE status = 'draft'
+  AND publish_at <= NOW()
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+RETURNING id, publish_at
+`
+
+type PublishScheduledProjectsRow struct {
+	ID        int64              `json:"id"`
+	PublishAt pgtype.Timestamptz `json:"publish_at"`
+}
+
+// Drafts whose publish_at has passed (and whose unpublish_at has not)
+func (q *Queries) PublishScheduledProjects(ctx context.Context) ([]PublishScheduledProjectsRow, error) {
+	rows, err := q.db.Query(ctx, publishScheduledProjects)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PublishScheduledProjectsRow
+	for rows.Next() {
+		var i PublishScheduledProjectsRow
+		if err := rows.Scan(&i.ID, &i.PublishAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setProjectOrder = `-- name: SetProjectOrder :exec
+UPDATE projects p
+SET "order" = o.position - 1,
+    updated_at = NOW()
+FROM unnest($1::bigint[]) WITH ORDINALITY AS o(id, position)
+WHERE p.id = o.id AND p."order" <> o.position - 1
+`
+
+// Numbers the projects listed in $1 0, 1, 2... in that order; rows already in place are left alone
+func (q *Queries) SetProjectOrder(ctx context.Context, dollar_1 []int64) error {
+	_, err := q.db.Exec(ctx, setProjectOrder, dollar_1)
+	return err
+}
+
 const toggleProjectHighlight = `-- name: ToggleProjectHighlight :exec
 UPDATE projects
 SET highlighted = NOT highlighted,
@@ -319,26 +889,79 @@ func (q *Queries) ToggleProjectHighlight(ctx context.Context, id int64) error {
 	return err
 }
 
+const unpublishScheduledProjects = `-- name: UnpublishScheduledProjects :many
+UPDATE projects
+SET status = 'archived',
+    updated_at = NOW()
+WHERE status = 'published'
+  AND unpublish_at <= NOW()
+RETURNING id, unpublish_at
+`
+
+type UnpublishScheduledProjectsRow struct {
+	ID          int64              `json:"id"`
+	UnpublishAt pgtype.Timestamptz `json:"unpublish_at"`
+}
+
+// Published projects whose unpublish_at has passed
+func (q *Queries) UnpublishScheduledProjects(ctx context.Context) ([]UnpublishScheduledProjectsRow, error) {
+	rows, err := q.db.Query(ctx, unpublishScheduledProjects)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UnpublishScheduledProjectsRow
+	for rows.Next() {
+		var i UnpublishScheduledProjectsRow
+		if err := rows.Scan(&i.ID, &i.UnpublishAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateProject = `-- name: UpdateProject :exec
 UPDATE projects
 SET status = $2,
     name = $3,
-    category = $4,
+    category_id = $4,
     client = $5,
     "order" = $6,
     highlighted = $7,
+    slug = $8,
+    publish_at = $9,
+    unpublish_at = $10,
+    description = $11,
+    location = $12,
+    completion_year = $13,
+    area_m2 = $14,
+    duration_months = $15,
+    services = $16,
     updated_at = NOW()
 WHERE id = $1
 `
 
 type UpdateProjectParams struct {
-	ID          int64       `json:"id"`
-	Status      int16       `json:"status"`
-	Name        string      `json:"name"`
-	Category    pgtype.Text `json:"category"`
-	Client      pgtype.Text `json:"client"`
-	Order       int32       `json:"order"`
-	Highlighted bool        `json:"highlighted"`
+	ID             int64              `json:"id"`
+	Status         string             `json:"status"`
+	Name           string             `json:"name"`
+	CategoryID     pgtype.Int8        `json:"category_id"`
+	Client         pgtype.Text        `json:"client"`
+	Order          int32              `json:"order"`
+	Highlighted    bool               `json:"highlighted"`
+	Slug           string             `json:"slug"`
+	PublishAt      pgtype.Timestamptz `json:"publish_at"`
+	UnpublishAt    pgtype.Timestamptz `json:"unpublish_at"`
+	Description    pgtype.Text        `json:"description"`
+	Location       pgtype.Text        `json:"location"`
+	CompletionYear pgtype.Int4        `json:"completion_year"`
+	AreaM2         pgtype.Float8      `json:"area_m2"`
+	DurationMonths pgtype.Int4        `json:"duration_months"`
+	Services       []string           `json:"services"`
 }
 
 func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) error {
@@ -346,10 +969,62 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) er
 		arg.ID,
 		arg.Status,
 		arg.Name,
-		arg.Category,
+		arg.CategoryID,
 		arg.Client,
 		arg.Order,
 		arg.Highlighted,
+		arg.Slug,
+		arg.PublishAt,
+		arg.UnpublishAt,
+		arg.Description,
+		arg.Location,
+		arg.CompletionYear,
+		arg.AreaM2,
+		arg.DurationMonths,
+		arg.Services,
 	)
 	return err
 }
+
+const updateProjectStatus = `-- name: UpdateProjectStatus :one
+UPDATE projects
+SET status = $2,
+    publish_at = CASE WHEN $2 <> 'published' AND publish_at <= NOW() THEN NULL ELSE publish_at END,
+    unpublish_at = CASE WHEN $2 = 'published' AND unpublish_at <= NOW() THEN NULL ELSE unpublish_at END,
+    updated_at = NOW()
+WHERE id = $1 AND status = ANY($3::text[])
+RETURNING id, status, name, client, "order", highlighted, created_at, updated_at, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, category_id
+`
+
+type UpdateProjectStatusParams struct {
+	ID      int64    `json:"id"`
+	Status  string   `json:"status"`
+	Column3 []string `json:"column_3"`
+}
+
+// Moves a project to a new status, only when its current status is one of $3
+func (q *Queries) UpdateProjectStatus(ctx context.Context, arg UpdateProjectStatusParams) (Project, error) {
+	row := q.db.QueryRow(ctx, updateProjectStatus, arg.ID, arg.Status, arg.Column3)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.Name,
+		&i.Client,
+		&i.Order,
+		&i.Highlighted,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Slug,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Description,
+		&i.Location,
+		&i.CompletionYear,
+		&i.AreaM2,
+		&i.DurationMonths,
+		&i.Services,
+		&i.CategoryID,
+	)
+	return i, err
+}
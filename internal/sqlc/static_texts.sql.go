@@ -7,6 +7,8 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countStaticTexts = `-- name: CountStaticTexts :one
@@ -105,6 +107,112 @@ func (q *Queries) ListStaticTexts(ctx context.Context, arg ListStaticTextsParams
 	return items, nil
 }
 
+const listStaticTextsKeyset = `-- name: ListStaticTextsKeyset :many
+SELECT id, key, label, content, created_at, updated_at FROM static_texts
+WHERE $3::bigint = 0
+   OR ($1::boolean AND (created_at, id) > ($2::timestamp, $3::bigint))
+   OR (NOT $1::boolean AND (created_at, id) < ($2::timestamp, $3::bigint))
+ORDER BY
+  CASE WHEN $1::boolean THEN created_at END ASC,
+  CASE WHEN $1::boolean THEN id END ASC,
+  CASE WHEN NOT $1::boolean THEN created_at END DESC,
+  CASE WHEN NOT $1::boolean THEN id END DESC
+LIMIT $4
+`
+
+type ListStaticTextsKeysetParams struct {
+	Column1 bool             `json:"column_1"`
+	Column2 pgtype.Timestamp `json:"column_2"`
+	Column3 int64            `json:"column_3"`
+	Limit   int32            `json:"limit"`
+}
+
+// Keyset pagination in created_at DESC, id DESC order: the rows after the cursor ($2, $3),
+// or before it when $1 is true (then in reverse order). $3 = 0 starts at the top.
+func (q *Queries) ListStaticTextsKeyset(ctx context.Context, arg ListStaticTextsKeysetParams) ([]StaticText, error) {
+	rows, err := q.db.Query(ctx, listStaticTextsKeyset,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StaticText
+	for rows.Next() {
+		var i StaticText
+		if err := rows.Scan(
+			&i.ID,
+			&i.Key,
+			&i.Label,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchStaticTexts = `-- name: SearchStaticTexts :many
+SELECT st.id, st.label AS title,
+    ts_headline('unaccent_simple', concat_ws(' · ', st.label, st.content), query,
+        'StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=25')::text AS snippet,
+    COUNT(*) OVER () AS total
+FROM static_texts st
+CROSS JOIN to_tsquery('unaccent_simple', $1::text) AS query
+CROSS JOIN LATERAL to_tsvector('unaccent_simple', concat_ws(' ', translate(st.key, '_.-', '   '), st.label, st.content)) AS document
+WHERE document @@ query
+ORDER BY ts_rank(document, query) DESC, st.key, st.id
+LIMIT $2
+`
+
+type SearchStaticTextsParams struct {
+	Column1 string `json:"column_1"`
+	Limit   int32  `json:"limit"`
+}
+
+type SearchStaticTextsRow struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+	Total   int64  `json:"total"`
+}
+
+// Admin search (tsquery $1, see searchQuery) in key, label and content, best matches first.
+// snippet has the matches in <mark> tags; total is the number of matches
+func (q *Queries) SearchStaticTexts(ctx context.Context, arg SearchStaticTextsParams) ([]SearchStaticTextsRow, error) {
+	rows, err := q.db.Query(ctx, searchStaticTexts, arg.Column1, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchStaticTextsRow
+	for rows.Next() {
+		var i SearchStaticTextsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Snippet,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateStaticTextContent = `-- name: UpdateStaticTextContent :exec
 UPDATE static_texts
 SET content = $2,
@@ -7,6 +7,8 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countTestimonials = `-- name: CountTestimonials :one
@@ -21,16 +23,18 @@ func (q *Queries) CountTestimonials(ctx context.Context) (int64, error) {
 }
 
 const createTestimonial = `-- name: CreateTestimonial :one
-INSERT INTO testimonials (full_name, profession, testimonial, status, created_at, updated_at)
-VALUES ($1, $2, $3, $4, NOW(), NOW())
-RETURNING id, full_name, profession, testimonial, status, created_at, updated_at
+INSERT INTO testimonials (full_name, profession, testimonial, status, publish_at, unpublish_at, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+RETURNING id, full_name, profession, testimonial, status, created_at, updated_at, publish_at, unpublish_at
 `
 
 type CreateTestimonialParams struct {
-	FullName    string `json:"full_name"`
-	Profession  string `json:"profession"`
-	Testimonial string `json:"testimonial"`
-	Status      string `json:"status"`
+	FullName    string             `json:"full_name"`
+	Profession  string             `json:"profession"`
+	Testimonial string             `json:"testimonial"`
+	Status      string             `json:"status"`
+	PublishAt   pgtype.Timestamptz `json:"publish_at"`
+	UnpublishAt pgtype.Timestamptz `json:"unpublish_at"`
 }
 
 func (q *Queries) CreateTestimonial(ctx context.Context, arg CreateTestimonialParams) (Testimonial, error) {
@@ -39,6 +43,8 @@ func (q *Queries) CreateTestimonial(ctx context.Context, arg CreateTestimonialPa
 		arg.Profession,
 		arg.Testimonial,
 		arg.Status,
+		arg.PublishAt,
+		arg.UnpublishAt,
 	)
 	var i Testimonial
 	err := row.Scan(
@@ -49,6 +55,8 @@ func (q *Queries) CreateTestimonial(ctx context.Context, arg CreateTestimonialPa
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.UnpublishAt,
 	)
 	return i, err
 }
@@ -63,7 +71,7 @@ func (q *Queries) DeleteTestimonial(ctx context.Context, id int64) error {
 }
 
 const getTestimonialByID = `-- name: GetTestimonialByID :one
-SELECT id, full_name, profession, testimonial, status, created_at, updated_at FROM testimonials WHERE id = $1
+SELECT id, full_name, profession, testimonial, status, created_at, updated_at, publish_at, unpublish_at FROM testimonials WHERE id = $1
 `
 
 func (q *Queries) GetTestimonialByID(ctx context.Context, id int64) (Testimonial, error) {
@@ -77,12 +85,18 @@ func (q *Queries) GetTestimonialByID(ctx context.Context, id int64) (Testimonial
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.UnpublishAt,
 	)
 	return i, err
 }
 
 const listPublicTestimonials = `-- name: ListPublicTestimonials :many
-SELECT id, full_name, profession, testimonial, status, created_at, updated_at FROM testimonials WHERE status = 'ready' ORDER BY created_at DESC
+SELECT id, full_name, profession, testimonial, status, created_at, updated_at, publish_at, unpublish_at FROM testimonials
+WHERE status = 'ready'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+ORDER BY created_at DESC
 `
 
 func (q *Queries) ListPublicTestimonials(ctx context.Context) ([]Testimonial, error) {
@@ -102,6 +116,8 @@ func (q *Queries) ListPublicTestimonials(ctx context.Context) ([]Testimonial, er
 			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PublishAt,
+			&i.UnpublishAt,
 		); err != nil {
 			return nil, err
 		}
@@ -114,7 +130,7 @@ func (q *Queries) ListPublicTestimonials(ctx context.Context) ([]Testimonial, er
 }
 
 const listTestimonials = `-- name: ListTestimonials :many
-SELECT id, full_name, profession, testimonial, status, created_at, updated_at FROM testimonials ORDER BY created_at DESC LIMIT $1 OFFSET $2
+SELECT id, full_name, profession, testimonial, status, created_at, updated_at, publish_at, unpublish_at FROM testimonials ORDER BY created_at DESC LIMIT $1 OFFSET $2
 `
 
 type ListTestimonialsParams struct {
@@ -139,6 +155,153 @@ func (q *Queries) ListTestimonials(ctx context.Context, arg ListTestimonialsPara
 			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PublishAt,
+			&i.UnpublishAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTestimonialsKeyset = `-- name: ListTestimonialsKeyset :many
+SELECT id, full_name, profession, testimonial, status, created_at, updated_at, publish_at, unpublish_at FROM testimonials
+WHERE $3::bigint = 0
+   OR ($1::boolean AND (created_at, id) > ($2::timestamp, $3::bigint))
+   OR (NOT $1::boolean AND (created_at, id) < ($2::timestamp, $3::bigint))
+ORDER BY
+  CASE WHEN $1::boolean THEN created_at END ASC,
+  CASE WHEN $1::boolean THEN id END ASC,
+  CASE WHEN NOT $1::boolean THEN created_at END DESC,
+  CASE WHEN NOT $1::boolean THEN id END DESC
+LIMIT $4
+`
+
+type ListTestimonialsKeysetParams struct {
+	Column1 bool             `json:"column_1"`
+	Column2 pgtype.Timestamp `json:"column_2"`
+	Column3 int64            `json:"column_3"`
+	Limit   int32            `json:"limit"`
+}
+
+// Keyset pagination in created_at DESC, id DESC order: the rows after the cursor ($2, $3),
+// or before it when $1 is true (then in reverse order). $3 = 0 starts at the top.
+func (q *Queries) ListTestimonialsKeyset(ctx context.Context, arg ListTestimonialsKeysetParams) ([]Testimonial, error) {
+	rows, err := q.db.Query(ctx, listTestimonialsKeyset,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Testimonial
+	for rows.Next() {
+		var i Testimonial
+		if err := rows.Scan(
+			&i.ID,
+			&i.FullName,
+			&i.Profession,
+			&i.Testimonial,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PublishAt,
+			&i.UnpublishAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishScheduledTestimonials = `-- name: PublishScheduledTestimonials :many
+UPDATE testimonials
+SET status = 'ready',
+    updated_at = NOW()
+WHERE status = 'pending'
+  AND publish_at <= NOW()
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+RETURNING id, publish_at
+`
+
+type PublishScheduledTestimonialsRow struct {
+	ID        int64              `json:"id"`
+	PublishAt pgtype.Timestamptz `json:"publish_at"`
+}
+
+// Pending testimonials whose publish_at has passed (and whose unpublish_at has not)
+func (q *Queries) PublishScheduledTestimonials(ctx context.Context) ([]PublishScheduledTestimonialsRow, error) {
+	rows, err := q.db.Query(ctx, publishScheduledTestimonials)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PublishScheduledTestimonialsRow
+	for rows.Next() {
+		var i PublishScheduledTestimonialsRow
+		if err := rows.Scan(&i.ID, &i.PublishAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTestimonials = `-- name: SearchTestimonials :many
+SELECT t.id, t.full_name AS title,
+    ts_headline('unaccent_simple', concat_ws(' · ', t.full_name, t.profession, t.testimonial), query,
+        'StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=25')::text AS snippet,
+    COUNT(*) OVER () AS total
+FROM testimonials t
+CROSS JOIN to_tsquery('unaccent_simple', $1::text) AS query
+CROSS JOIN LATERAL to_tsvector('unaccent_simple', concat_ws(' ', t.full_name, t.profession, t.testimonial)) AS document
+WHERE document @@ query
+ORDER BY ts_rank(document, query) DESC, t.created_at DESC, t.id DESC
+LIMIT $2
+`
+
+type SearchTestimonialsParams struct {
+	Column1 string `json:"column_1"`
+	Limit   int32  `json:"limit"`
+}
+
+type SearchTestimonialsRow struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+	Total   int64  `json:"total"`
+}
+
+// Admin search (tsquery $1, see searchQuery) in name, profession and text, best matches first.
+// snippet has the matches in <mark> tags; total is the number of matches
+func (q *Queries) SearchTestimonials(ctx context.Context, arg SearchTestimonialsParams) ([]SearchTestimonialsRow, error) {
+	rows, err := q.db.Query(ctx, searchTestimonials, arg.Column1, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchTestimonialsRow
+	for rows.Next() {
+		var i SearchTestimonialsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Snippet,
+			&i.Total,
 		); err != nil {
 			return nil, err
 		}
@@ -150,24 +313,64 @@ func (q *Queries) ListTestimonials(ctx context.Context, arg ListTestimonialsPara
 	return items, nil
 }
 
+const unpublishScheduledTestimonials = `-- name: UnpublishScheduledTestimonials :many
+UPDATE testimonials
+SET status = 'pending',
+    updated_at = NOW()
+WHERE status = 'ready'
+  AND unpublish_at <= NOW()
+RETURNING id, unpublish_at
+`
+
+type UnpublishScheduledTestimonialsRow struct {
+	ID          int64              `json:"id"`
+	UnpublishAt pgtype.Timestamptz `json:"unpublish_at"`
+}
+
+// Ready testimonials whose unpublish_at has passed
+func (q *Queries) UnpublishScheduledTestimonials(ctx context.Context) ([]UnpublishScheduledTestimonialsRow, error) {
+	rows, err := q.db.Query(ctx, unpublishScheduledTestimonials)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UnpublishScheduledTestimonialsRow
+	for rows.Next() {
+		var i UnpublishScheduledTestimonialsRow
+		if err := rows.Scan(&i.ID, &i.UnpublishAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateTestimonial = `-- name: UpdateTestimonial :exec
 UPDATE testimonials
 SET full_name = $2,
     profession = $3,
     testimonial = $4,
     status = $5,
+    publish_at = CASE WHEN $5 <> 'ready' AND $6::timestamptz <= NOW() THEN NULL ELSE $6::timestamptz END,
+    unpublish_at = CASE WHEN $5 = 'ready' AND $7::timestamptz <= NOW() THEN NULL ELSE $7::timestamptz END,
     updated_at = NOW()
 WHERE id = $1
 `
 
 type UpdateTestimonialParams struct {
-	ID          int64  `json:"id"`
-	FullName    string `json:"full_name"`
-	Profession  string `json:"profession"`
-	Testimonial string `json:"testimonial"`
-	Status      string `json:"status"`
+	ID          int64              `json:"id"`
+	FullName    string             `json:"full_name"`
+	Profession  string             `json:"profession"`
+	Testimonial string             `json:"testimonial"`
+	Status      string             `json:"status"`
+	PublishAt   pgtype.Timestamptz `json:"publish_at"`
+	UnpublishAt pgtype.Timestamptz `json:"unpublish_at"`
 }
 
+// A schedule that already fired is cleared so the scheduler does not undo the status change.
 func (q *Queries) UpdateTestimonial(ctx context.Context, arg UpdateTestimonialParams) error {
 	_, err := q.db.Exec(ctx, updateTestimonial,
 		arg.ID,
@@ -175,6 +378,8 @@ func (q *Queries) UpdateTestimonial(ctx context.Context, arg UpdateTestimonialPa
 		arg.Profession,
 		arg.Testimonial,
 		arg.Status,
+		arg.PublishAt,
+		arg.UnpublishAt,
 	)
 	return err
 }
@@ -178,6 +178,117 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const listUsersKeyset = `-- name: ListUsersKeyset :many
+SELECT id, name, email, email_verified_at, password, password_reset_required, reset_token_hash, reset_token_expires_at, remember_token, created_at, updated_at FROM users
+WHERE $3::bigint = 0
+   OR ($1::boolean AND (created_at, id) > ($2::timestamp, $3::bigint))
+   OR (NOT $1::boolean AND (created_at, id) < ($2::timestamp, $3::bigint))
+ORDER BY
+  CASE WHEN $1::boolean THEN created_at END ASC,
+  CASE WHEN $1::boolean THEN id END ASC,
+  CASE WHEN NOT $1::boolean THEN created_at END DESC,
+  CASE WHEN NOT $1::boolean THEN id END DESC
+LIMIT $4
+`
+
+type ListUsersKeysetParams struct {
+	Column1 bool             `json:"column_1"`
+	Column2 pgtype.Timestamp `json:"column_2"`
+	Column3 int64            `json:"column_3"`
+	Limit   int32            `json:"limit"`
+}
+
+// Keyset pagination in created_at DESC, id DESC order: the rows after the cursor ($2, $3),
+// or before it when $1 is true (then in reverse order). $3 = 0 starts at the top.
+func (q *Queries) ListUsersKeyset(ctx context.Context, arg ListUsersKeysetParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersKeyset,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.EmailVerifiedAt,
+			&i.Password,
+			&i.PasswordResetRequired,
+			&i.ResetTokenHash,
+			&i.ResetTokenExpiresAt,
+			&i.RememberToken,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT u.id, u.name AS title,
+    ts_headline('unaccent_simple', concat_ws(' · ', u.name, u.email), query,
+        'StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=25')::text AS snippet,
+    COUNT(*) OVER () AS total
+FROM users u
+CROSS JOIN to_tsquery('unaccent_simple', $1::text) AS query
+CROSS JOIN LATERAL to_tsvector('unaccent_simple', concat_ws(' ', u.name, translate(u.email, '@.', '  '))) AS document
+WHERE document @@ query
+ORDER BY ts_rank(document, query) DESC, u.name, u.id
+LIMIT $2
+`
+
+type SearchUsersParams struct {
+	Column1 string `json:"column_1"`
+	Limit   int32  `json:"limit"`
+}
+
+type SearchUsersRow struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+	Total   int64  `json:"total"`
+}
+
+// Admin search (tsquery $1, see searchQuery) in name and email, best matches first.
+// snippet has the matches in <mark> tags; total is the number of matches
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	rows, err := q.db.Query(ctx, searchUsers, arg.Column1, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUsersRow
+	for rows.Next() {
+		var i SearchUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Snippet,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateUser = `-- name: UpdateUser :exec
 UPDATE users
 SET name = $2, 
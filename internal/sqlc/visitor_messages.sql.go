@@ -7,6 +7,8 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countVisitorMessages = `-- name: CountVisitorMessages :one
@@ -98,3 +100,110 @@ func (q *Queries) ListVisitorMessages(ctx context.Context, arg ListVisitorMessag
 	}
 	return items, nil
 }
+
+const listVisitorMessagesKeyset = `-- name: ListVisitorMessagesKeyset :many
+SELECT id, email, address, description, seen, created_at, updated_at FROM visitor_messages
+WHERE $3::bigint = 0
+   OR ($1::boolean AND (created_at, id) > ($2::timestamp, $3::bigint))
+   OR (NOT $1::boolean AND (created_at, id) < ($2::timestamp, $3::bigint))
+ORDER BY
+  CASE WHEN $1::boolean THEN created_at END ASC,
+  CASE WHEN $1::boolean THEN id END ASC,
+  CASE WHEN NOT $1::boolean THEN created_at END DESC,
+  CASE WHEN NOT $1::boolean THEN id END DESC
+LIMIT $4
+`
+
+type ListVisitorMessagesKeysetParams struct {
+	Column1 bool             `json:"column_1"`
+	Column2 pgtype.Timestamp `json:"column_2"`
+	Column3 int64            `json:"column_3"`
+	Limit   int32            `json:"limit"`
+}
+
+// Keyset pagination in created_at DESC, id DESC order: the rows after the cursor ($2, $3),
+// or before it when $1 is true (then in reverse order). $3 = 0 starts at the top.
+func (q *Queries) ListVisitorMessagesKeyset(ctx context.Context, arg ListVisitorMessagesKeysetParams) ([]VisitorMessage, error) {
+	rows, err := q.db.Query(ctx, listVisitorMessagesKeyset,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VisitorMessage
+	for rows.Next() {
+		var i VisitorMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Address,
+			&i.Description,
+			&i.Seen,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchVisitorMessages = `-- name: SearchVisitorMessages :many
+SELECT m.id, m.email AS title,
+    ts_headline('unaccent_simple', concat_ws(' · ', m.email, m.address, m.description), query,
+        'StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=25')::text AS snippet,
+    COUNT(*) OVER () AS total
+FROM visitor_messages m
+CROSS JOIN to_tsquery('unaccent_simple', $1::text) AS query
+CROSS JOIN LATERAL to_tsvector('unaccent_simple', concat_ws(' ', translate(m.email, '@.', '  '), m.address, m.description)) AS document
+WHERE document @@ query
+ORDER BY ts_rank(document, query) DESC, m.created_at DESC, m.id DESC
+LIMIT $2
+`
+
+type SearchVisitorMessagesParams struct {
+	Column1 string `json:"column_1"`
+	Limit   int32  `json:"limit"`
+}
+
+type SearchVisitorMessagesRow struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+	Total   int64  `json:"total"`
+}
+
+// Admin search (tsquery $1, see searchQuery) in email, address and message, best matches first.
+// snippet has the matches in <mark> tags; total is the number of matches
+func (q *Queries) SearchVisitorMessages(ctx context.Context, arg SearchVisitorMessagesParams) ([]SearchVisitorMessagesRow, error) {
+	rows, err := q.db.Query(ctx, searchVisitorMessages, arg.Column1, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchVisitorMessagesRow
+	for rows.Next() {
+		var i SearchVisitorMessagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Snippet,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+)
+
+// ErrNotFound is returned by a Backend when the requested key does not exist
+var ErrNotFound = errors.New("file not found")
+
+// Backend is a storage driver for uploaded files.
+// Keys are slash-separated paths relative to the storage root, e.g. img/<sha1>.jpg
+type Backend interface {
+	// Put stores the content of r under key, overwriting any existing file
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the file stored under key. The caller must close the returned reader
+	Get(ctx context.Context, key string) (io.ReadCloser, *FileInfo, error)
+	// Delete removes the file stored under key. Deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+	// Stat returns metadata for the file stored under key
+	Stat(ctx context.Context, key string) (*FileInfo, error)
+	// List returns all files whose key starts with prefix
+	List(ctx context.Context, prefix string) ([]FileInfo, error)
+	// URL returns the public URL the file can be fetched from
+	URL(key string) string
+}
+
+// FileInfo describes a stored file
+type FileInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Store holds the configured storage backend
+var Store Backend
+
+// Setup initializes the storage backend selected by cfg.StorageDriver
+func Setup(cfg *config.Config) error {
+	switch cfg.StorageDriver {
+	case "", "local":
+		Store = NewLocalBackend(cfg.StoragePath)
+	case "s3":
+		backend, err := NewS3Backend(context.Background(), cfg.S3)
+		if err != nil {
+			return fmt.Errorf("failed to initialize s3 storage: %w", err)
+		}
+		Store = backend
+	default:
+		return fmt.Errorf("unknown storage driver: %s", cfg.StorageDriver)
+	}
+
+	// Resumable uploads are always assembled on local disk
+	Chunks = NewChunkStore(cfg.StoragePath)
+
+	// Resized images are cached on local disk
+	cache, err := NewDiskCache(cfg.Resize.CacheDir, cfg.Resize.CacheSize)
+	if err != nil {
+		return fmt.Errorf("failed to initialize resize cache: %w", err)
+	}
+	ResizeCache = cache
+	return nil
+}
+
+// urlPrefix is the path prefix under which stored files are served by the API
+const urlPrefix = "/storage/"
+
+// PathForKey returns the API path stored in the database for a storage key
+// (img/abc.jpg -> /storage/img/abc.jpg)
+func PathForKey(key string) string {
+	return urlPrefix + key
+}
+
+// KeyFromURL extracts the storage key from a path stored in the database
+// (/storage/img/abc.jpg -> img/abc.jpg)
+func KeyFromURL(url string) (string, error) {
+	if !strings.HasPrefix(url, urlPrefix+"img/") {
+		return "", fmt.Errorf("invalid URL format: %s", url)
+	}
+	return strings.TrimPrefix(url, urlPrefix), nil
+}
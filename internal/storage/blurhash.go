@@ -1,40 +1,80 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"image"
-	"os"
+	"image/png"
+	"io"
+	"strings"
 
 	"github.com/buckket/go-blurhash"
 	_ "image/jpeg"
-	_ "image/png"
 )
 
-// GenerateBlurHash generates a blurhash from an image file and returns it as a data URL
-func GenerateBlurHash(filePath string) (string, error) {
-	// Open and decode the image
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
+const (
+	// blurHashSampleWidth is the width images are downscaled to before encoding; blurhash
+	// only keeps a handful of components so encoding full-size photos is wasted work
+	blurHashSampleWidth = 64
+
+	// PlaceholderSize is the default edge length of decoded placeholder PNGs
+	PlaceholderSize = 32
+	// MaxPlaceholderSize caps placeholder dimensions requested by clients
+	MaxPlaceholderSize = 128
+
+	// legacyBlurHashPrefix marks blurhashes stored by older versions as base64 text data URLs
+	legacyBlurHashPrefix = "data:text/plain;base64,"
+)
 
-	img, _, err := image.Decode(file)
+// GenerateBlurHash generates a blurhash string from image data
+func GenerateBlurHash(r io.Reader) (string, error) {
+	// Decode the image
+	img, _, err := image.Decode(r)
 	if err != nil {
 		return "", fmt.Errorf("failed to decode image: %w", err)
 	}
 
+	if img.Bounds().Dx() > blurHashSampleWidth {
+		img = resizeToWidth(img, blurHashSampleWidth, false)
+	}
+
 	// Generate blurhash (components: 4x4 is a good balance)
 	hash, err := blurhash.Encode(4, 4, img)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate blurhash: %w", err)
 	}
 
-	// Convert to data URL format: data:image/svg+xml;base64,<base64-encoded-svg>
-	// For blurhash, we'll use a simple data URL format
-	// Actually, blurhash is just a string, so we'll return it as-is
-	// But the spec says "data URL format", so let's encode it as base64 in a data URL
-	encoded := base64.StdEncoding.EncodeToString([]byte(hash))
-	return fmt.Sprintf("data:text/plain;base64,%s", encoded), nil
+	return hash, nil
+}
+
+// NormalizeBlurHash returns the raw blurhash for values stored in the legacy data URL format
+func NormalizeBlurHash(value string) string {
+	if !strings.HasPrefix(value, legacyBlurHashPrefix) {
+		return value
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, legacyBlurHashPrefix))
+	if err != nil {
+		return value
+	}
+	return string(decoded)
+}
+
+// RenderBlurHashPNG decodes a blurhash into a PNG image of the given size
+func RenderBlurHashPNG(hash string, width, height int) ([]byte, error) {
+	if width <= 0 || height <= 0 || width > MaxPlaceholderSize || height > MaxPlaceholderSize {
+		return nil, fmt.Errorf("invalid placeholder size %dx%d", width, height)
+	}
+
+	img, err := blurhash.Decode(NormalizeBlurHash(hash), width, height, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode blurhash: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode placeholder: %w", err)
+	}
+	return buf.Bytes(), nil
 }
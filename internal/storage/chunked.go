@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+)
+
+// Chunks holds resumable uploads in progress, initialized by Setup
+var Chunks *ChunkStore
+
+var uploadIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// ErrChunkDataMissing is returned by Append when the data received so far is not on this
+// instance's disk (e.g. the upload was started on another replica)
+var ErrChunkDataMissing = errors.New("upload data missing")
+
+// ChunkStore assembles resumable uploads on local disk (<STORAGE_PATH>/uploads) until they are finalized.
+// Unlike the storage backend this is single-instance only: both the partial files and the chunk locks
+// are local to the process, so every request of an upload must reach the same API instance
+// (one replica, or sticky sessions). A shared STORAGE_PATH is not enough, the locks are not shared
+type ChunkStore struct {
+	dir string
+
+	mu      sync.Mutex
+	writing map[string]bool // uploads with a chunk being written
+}
+
+// NewChunkStore creates a chunk store under storagePath
+func NewChunkStore(storagePath string) *ChunkStore {
+	return &ChunkStore{dir: filepath.Join(storagePath, "uploads"), writing: make(map[string]bool)}
+}
+
+// TryLock reserves an upload for writing a chunk. It returns false if another chunk of the
+// same upload is still being written; otherwise the returned function releases the upload
+func (s *ChunkStore) TryLock(id string) (func(), bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.writing[id] {
+		return nil, false
+	}
+	s.writing[id] = true
+	return func() {
+		s.mu.Lock()
+		delete(s.writing, id)
+		s.mu.Unlock()
+	}, true
+}
+
+// NewUploadID returns a random, unguessable upload ID
+func NewUploadID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Path returns the path of the file an upload is assembled in
+func (s *ChunkStore) Path(id string) (string, error) {
+	if !uploadIDPattern.MatchString(id) {
+		return "", fmt.Errorf("invalid upload id")
+	}
+	return filepath.Join(s.dir, id), nil
+}
+
+// Append writes a chunk at offset, discarding anything previously written past it.
+// It returns the number of bytes written, which may be short (with an error) if r fails midway,
+// and ErrChunkDataMissing if fewer than offset bytes are on disk. Callers must hold the upload's TryLock
+func (s *ChunkStore) Append(id string, offset int64, r io.Reader) (int64, error) {
+	path, err := s.Path(id)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return 0, err
+	}
+
+	// Never zero-fill: the bytes before offset must really be there
+	if offset > 0 {
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) || (err == nil && info.Size() < offset) {
+			return 0, ErrChunkDataMissing
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if err := f.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		return n, err
+	}
+	return n, f.Sync()
+}
+
+// Inspect returns the hex SHA1 and sniffed content type of an assembled upload
+func (s *ChunkStore) Inspect(id string) (string, string, error) {
+	path, err := s.Path(id)
+	if err != nil {
+		return "", "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", "", err
+	}
+	contentType := http.DetectContentType(head[:n])
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", "", err
+	}
+	hash := sha1.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), contentType, nil
+}
+
+// Remove deletes an upload's file, if any
+func (s *ChunkStore) Remove(id string) error {
+	path, err := s.Path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// CleanupExpiredUploads deletes uploads that were neither finished nor attached before they expired
+func CleanupExpiredUploads(ctx context.Context, queries *sqlc.Queries) (int, error) {
+	ids, err := queries.ListExpiredUploadIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired uploads: %w", err)
+	}
+
+	removed := 0
+	for _, id := range ids {
+		if err := queries.DeleteUpload(ctx, id); err != nil {
+			return removed, fmt.Errorf("failed to delete upload %s: %w", id, err)
+		}
+		if err := Chunks.Remove(id); err != nil {
+			log.Printf("Failed to remove upload file %s: %v", id, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// RunUploadCleanupPeriodically runs CleanupExpiredUploads every interval until ctx is cancelled
+func RunUploadCleanupPeriodically(ctx context.Context, queries *sqlc.Queries, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := CleanupExpiredUploads(ctx, queries)
+			if err != nil {
+				log.Printf("Upload cleanup failed: %v", err)
+				continue
+			}
+			if removed > 0 {
+				log.Printf("Upload cleanup: removed %d expired uploads", removed)
+			}
+		}
+	}
+}
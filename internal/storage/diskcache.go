@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"container/list"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ResizeCache caches on-the-fly resized images, initialized by Setup
+var ResizeCache *DiskCache
+
+var cacheNamePattern = regexp.MustCompile(`^[0-9a-f]{40}\.[a-z]+$`)
+
+// DiskCache is a size-capped directory of files evicted in least recently used order.
+// The LRU order survives restarts through the files' modification times
+type DiskCache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List // front = most recently used
+	entries map[string]*list.Element
+}
+
+type diskCacheEntry struct {
+	name string
+	size int64
+}
+
+// NewDiskCache opens (or creates) a cache directory, indexing the files already in it
+func NewDiskCache(dir string, maxSize int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c := &DiskCache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	type existing struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	var files []existing
+	for _, e := range dirEntries {
+		if !cacheNamePattern.MatchString(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, existing{e.Name(), info.Size(), info.ModTime()})
+	}
+
+	// Oldest first, so the newest end up at the front
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		c.entries[f.name] = c.lru.PushFront(&diskCacheEntry{f.name, f.size})
+		c.size += f.size
+	}
+
+	c.mu.Lock()
+	c.evictLocked()
+	c.mu.Unlock()
+
+	return c, nil
+}
+
+// Get returns the path of a cached file and marks it as recently used
+func (c *DiskCache) Get(name string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[name]
+	if !ok {
+		return "", false
+	}
+	c.lru.MoveToFront(elem)
+
+	path := filepath.Join(c.dir, name)
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return path, true
+}
+
+// Put stores a file in the cache, evicting least recently used files to stay under the size cap
+func (c *DiskCache) Put(name string, data []byte) error {
+	if !cacheNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid cache name: %s", name)
+	}
+	if int64(len(data)) > c.maxSize {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, name)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[name]; ok {
+		entry := elem.Value.(*diskCacheEntry)
+		c.size -= entry.size
+		entry.size = int64(len(data))
+		c.lru.MoveToFront(elem)
+	} else {
+		c.entries[name] = c.lru.PushFront(&diskCacheEntry{name, int64(len(data))})
+	}
+	c.size += int64(len(data))
+	c.evictLocked()
+
+	return nil
+}
+
+// evictLocked removes least recently used files until the cache fits its size cap
+func (c *DiskCache) evictLocked() {
+	for c.size > c.maxSize {
+		elem := c.lru.Back()
+		if elem == nil {
+			return
+		}
+		entry := elem.Value.(*diskCacheEntry)
+		if err := os.Remove(filepath.Join(c.dir, entry.name)); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to evict cached image %s: %v", entry.name, err)
+		}
+		c.lru.Remove(elem)
+		delete(c.entries, entry.name)
+		c.size -= entry.size
+	}
+}
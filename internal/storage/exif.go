@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ImageMetadata holds the EXIF fields the upload pipeline cares about
+type ImageMetadata struct {
+	Orientation int // 1-8, 1 (or 0) means no transform needed
+	CapturedAt  *time.Time
+	Latitude    *float64
+	Longitude   *float64
+}
+
+// EXIF tags
+const (
+	tagOrientation      = 0x0112
+	tagDateTime         = 0x0132
+	tagExifIFD          = 0x8769
+	tagGPSIFD           = 0x8825
+	tagDateTimeOriginal = 0x9003
+	tagGPSLatitudeRef   = 0x0001
+	tagGPSLatitude      = 0x0002
+	tagGPSLongitudeRef  = 0x0003
+	tagGPSLongitude     = 0x0004
+)
+
+// EXIF value types
+const (
+	exifTypeASCII    = 2
+	exifTypeShort    = 3
+	exifTypeLong     = 4
+	exifTypeRational = 5
+)
+
+var errInvalidExif = errors.New("invalid exif data")
+
+// exifEntry is a raw IFD entry
+type exifEntry struct {
+	typ   uint16
+	count uint32
+	value []byte // the 4 inline bytes or the referenced data
+}
+
+// tiffReader reads IFDs from a TIFF structure (the payload of an EXIF block)
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// parseExif parses a TIFF-structured EXIF payload (starting at the II/MM byte-order mark)
+func parseExif(data []byte) (*ImageMetadata, error) {
+	if len(data) < 8 {
+		return nil, errInvalidExif
+	}
+
+	r := &tiffReader{data: data}
+	switch string(data[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return nil, errInvalidExif
+	}
+	if r.order.Uint16(data[2:4]) != 42 {
+		return nil, errInvalidExif
+	}
+
+	ifd0, err := r.readIFD(r.order.Uint32(data[4:8]))
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &ImageMetadata{}
+	if e, ok := ifd0[tagOrientation]; ok {
+		meta.Orientation = int(r.uint(e))
+	}
+
+	// Capture date: DateTimeOriginal from the Exif IFD, falling back to IFD0 DateTime
+	dateStr := ""
+	if e, ok := ifd0[tagExifIFD]; ok {
+		if exifIFD, err := r.readIFD(r.uint(e)); err == nil {
+			if e, ok := exifIFD[tagDateTimeOriginal]; ok {
+				dateStr = r.ascii(e)
+			}
+		}
+	}
+	if e, ok := ifd0[tagDateTime]; ok && dateStr == "" {
+		dateStr = r.ascii(e)
+	}
+	if t, err := time.Parse("2006:01:02 15:04:05", dateStr); err == nil {
+		meta.CapturedAt = &t
+	}
+
+	// GPS position
+	if e, ok := ifd0[tagGPSIFD]; ok {
+		if gpsIFD, err := r.readIFD(r.uint(e)); err == nil {
+			meta.Latitude = r.coordinate(gpsIFD[tagGPSLatitude], gpsIFD[tagGPSLatitudeRef], "S")
+			meta.Longitude = r.coordinate(gpsIFD[tagGPSLongitude], gpsIFD[tagGPSLongitudeRef], "W")
+		}
+	}
+
+	return meta, nil
+}
+
+// readIFD reads the entries of the IFD at offset, keyed by tag
+func (r *tiffReader) readIFD(offset uint32) (map[uint16]exifEntry, error) {
+	if int(offset)+2 > len(r.data) {
+		return nil, errInvalidExif
+	}
+	count := int(r.order.Uint16(r.data[offset:]))
+	start := int(offset) + 2
+	if start+count*12 > len(r.data) {
+		return nil, errInvalidExif
+	}
+
+	entries := make(map[uint16]exifEntry, count)
+	for i := 0; i < count; i++ {
+		raw := r.data[start+i*12 : start+(i+1)*12]
+		e := exifEntry{
+			typ:   r.order.Uint16(raw[2:4]),
+			count: r.order.Uint32(raw[4:8]),
+			value: raw[8:12],
+		}
+
+		size := exifTypeSize(e.typ) * int(e.count)
+		if size > 4 {
+			valueOffset := int(r.order.Uint32(raw[8:12]))
+			if valueOffset < 0 || valueOffset+size > len(r.data) {
+				continue
+			}
+			e.value = r.data[valueOffset : valueOffset+size]
+		}
+		entries[r.order.Uint16(raw[0:2])] = e
+	}
+
+	return entries, nil
+}
+
+// uint returns the first SHORT/LONG value of an entry
+func (r *tiffReader) uint(e exifEntry) uint32 {
+	switch e.typ {
+	case exifTypeShort:
+		return uint32(r.order.Uint16(e.value))
+	case exifTypeLong:
+		return r.order.Uint32(e.value)
+	}
+	return 0
+}
+
+// ascii returns an ASCII entry without its NUL terminator
+func (r *tiffReader) ascii(e exifEntry) string {
+	if e.typ != exifTypeASCII {
+		return ""
+	}
+	return strings.TrimRight(string(e.value), "\x00 ")
+}
+
+// coordinate converts a degrees/minutes/seconds RATIONAL triple into signed decimal degrees
+func (r *tiffReader) coordinate(value, ref exifEntry, negativeRef string) *float64 {
+	if value.typ != exifTypeRational || value.count < 3 || len(value.value) < 24 {
+		return nil
+	}
+
+	var parts [3]float64
+	for i := range parts {
+		num := r.order.Uint32(value.value[i*8:])
+		den := r.order.Uint32(value.value[i*8+4:])
+		if den == 0 {
+			return nil
+		}
+		parts[i] = float64(num) / float64(den)
+	}
+
+	degrees := parts[0] + parts[1]/60 + parts[2]/3600
+	if r.ascii(ref) == negativeRef {
+		degrees = -degrees
+	}
+	return &degrees
+}
+
+// exifTypeSize returns the byte size of one value of the given type
+func exifTypeSize(typ uint16) int {
+	switch typ {
+	case 1, 2, 6, 7: // BYTE, ASCII, SBYTE, UNDEFINED
+		return 1
+	case 3, 8: // SHORT, SSHORT
+		return 2
+	case 4, 9, 11: // LONG, SLONG, FLOAT
+		return 4
+	case 5, 10, 12: // RATIONAL, SRATIONAL, DOUBLE
+		return 8
+	}
+	return 1
+}
@@ -0,0 +1,243 @@
+package storage
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// testEntry is an IFD entry for buildTIFF; values longer than 4 bytes are stored out of line
+type testEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// ifdSize returns the number of bytes buildIFD writes for entries
+func ifdSize(entries []testEntry) int {
+	size := 2 + 12*len(entries) + 4
+	for _, e := range entries {
+		if len(e.value) > 4 {
+			size += len(e.value)
+		}
+	}
+	return size
+}
+
+// buildIFD encodes an IFD that will be placed at offset within the TIFF payload
+func buildIFD(order binary.ByteOrder, offset int, entries []testEntry) []byte {
+	out := make([]byte, 2+12*len(entries)+4)
+	order.PutUint16(out, uint16(len(entries)))
+
+	var data []byte
+	dataOffset := offset + len(out)
+	for i, e := range entries {
+		raw := out[2+i*12:]
+		order.PutUint16(raw[0:], e.tag)
+		order.PutUint16(raw[2:], e.typ)
+		order.PutUint32(raw[4:], e.count)
+		if len(e.value) > 4 {
+			order.PutUint32(raw[8:], uint32(dataOffset+len(data)))
+			data = append(data, e.value...)
+		} else {
+			copy(raw[8:12], e.value)
+		}
+	}
+	return append(out, data...)
+}
+
+// buildTIFF encodes a TIFF payload with IFD0 at offset 8 followed by the given sub-IFDs.
+// Entries of IFD0 whose tag is in pointers get the offset of the matching sub-IFD as value
+func buildTIFF(order binary.ByteOrder, ifd0 []testEntry, pointers map[uint16][]testEntry) []byte {
+	header := make([]byte, 8)
+	if order == binary.LittleEndian {
+		copy(header, "II")
+	} else {
+		copy(header, "MM")
+	}
+	order.PutUint16(header[2:], 42)
+	order.PutUint32(header[4:], 8)
+
+	// Sub-IFDs follow IFD0 in tag order
+	tags := make([]uint16, 0, len(pointers))
+	for tag := range pointers {
+		tags = append(tags, tag)
+		ifd0 = append(ifd0, testEntry{tag: tag, typ: exifTypeLong, count: 1, value: make([]byte, 4)})
+	}
+	offset := 8 + ifdSize(ifd0)
+	subIFDs := make([]byte, 0)
+	for _, tag := range tags {
+		for i := range ifd0 {
+			if ifd0[i].tag == tag {
+				order.PutUint32(ifd0[i].value, uint32(offset+len(subIFDs)))
+			}
+		}
+		subIFDs = append(subIFDs, buildIFD(order, offset+len(subIFDs), pointers[tag])...)
+	}
+
+	out := append(header, buildIFD(order, 8, ifd0)...)
+	return append(out, subIFDs...)
+}
+
+func shortValue(order binary.ByteOrder, v uint16) []byte {
+	b := make([]byte, 2)
+	order.PutUint16(b, v)
+	return b
+}
+
+func asciiEntry(tag uint16, s string) testEntry {
+	value := append([]byte(s), 0)
+	return testEntry{tag: tag, typ: exifTypeASCII, count: uint32(len(value)), value: value}
+}
+
+func rationalEntry(order binary.ByteOrder, tag uint16, parts ...uint32) testEntry {
+	value := make([]byte, 4*len(parts))
+	for i, p := range parts {
+		order.PutUint32(value[i*4:], p)
+	}
+	return testEntry{tag: tag, typ: exifTypeRational, count: uint32(len(parts) / 2), value: value}
+}
+
+func orientationEntry(order binary.ByteOrder, orientation uint16) testEntry {
+	return testEntry{tag: tagOrientation, typ: exifTypeShort, count: 1, value: shortValue(order, orientation)}
+}
+
+func TestParseExif(t *testing.T) {
+	le, be := binary.LittleEndian, binary.BigEndian
+	captured := time.Date(2023, 5, 17, 14, 30, 0, 0, time.UTC)
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	gps := map[uint16][]testEntry{tagGPSIFD: {
+		asciiEntry(tagGPSLatitudeRef, "N"),
+		rationalEntry(le, tagGPSLatitude, 44, 1, 48, 1, 3600, 100),
+		asciiEntry(tagGPSLongitudeRef, "W"),
+		rationalEntry(le, tagGPSLongitude, 20, 1, 27, 1, 0, 1),
+	}}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    ImageMetadata
+		wantErr bool
+	}{
+		{
+			name: "little endian orientation",
+			data: buildTIFF(le, []testEntry{orientationEntry(le, 6)}, nil),
+			want: ImageMetadata{Orientation: 6},
+		},
+		{
+			name: "big endian orientation",
+			data: buildTIFF(be, []testEntry{orientationEntry(be, 8)}, nil),
+			want: ImageMetadata{Orientation: 8},
+		},
+		{
+			name: "long orientation",
+			data: buildTIFF(le, []testEntry{{tag: tagOrientation, typ: exifTypeLong, count: 1, value: []byte{3, 0, 0, 0}}}, nil),
+			want: ImageMetadata{Orientation: 3},
+		},
+		{
+			name: "date time original preferred",
+			data: buildTIFF(le, []testEntry{asciiEntry(tagDateTime, "2024:01:02 03:04:05")},
+				map[uint16][]testEntry{tagExifIFD: {asciiEntry(tagDateTimeOriginal, "2023:05:17 14:30:00")}}),
+			want: ImageMetadata{CapturedAt: &captured},
+		},
+		{
+			name: "date time fallback",
+			data: buildTIFF(le, []testEntry{asciiEntry(tagDateTime, "2024:01:02 03:04:05")}, nil),
+			want: ImageMetadata{CapturedAt: &modified},
+		},
+		{
+			name: "unparseable date ignored",
+			data: buildTIFF(le, []testEntry{asciiEntry(tagDateTime, "yesterday")}, nil),
+			want: ImageMetadata{},
+		},
+		{
+			name: "gps",
+			data: buildTIFF(le, nil, gps),
+			want: ImageMetadata{Latitude: float64Ptr(44.81), Longitude: float64Ptr(-20.45)},
+		},
+		{
+			name: "gps with zero denominator ignored",
+			data: buildTIFF(le, nil, map[uint16][]testEntry{tagGPSIFD: {
+				rationalEntry(le, tagGPSLatitude, 44, 1, 48, 0, 0, 1),
+			}}),
+			want: ImageMetadata{},
+		},
+		{name: "empty", data: nil, wantErr: true},
+		{name: "too short", data: []byte("II*\x00"), wantErr: true},
+		{name: "bad byte order", data: []byte("XX*\x00\x08\x00\x00\x00\x00\x00"), wantErr: true},
+		{name: "bad magic", data: []byte("II+\x00\x08\x00\x00\x00\x00\x00"), wantErr: true},
+		{name: "ifd0 offset past end", data: []byte("II*\x00\xff\x00\x00\x00"), wantErr: true},
+		{name: "ifd0 offset overflow", data: []byte("II*\x00\xff\xff\xff\xff"), wantErr: true},
+		{name: "truncated entries", data: []byte("II*\x00\x08\x00\x00\x00\x05\x00\x12\x01"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExif(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExif() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			assertMetadata(t, got, &tt.want)
+		})
+	}
+}
+
+// TestParseExifOutOfRange checks that entries pointing outside the payload are skipped, not read
+func TestParseExifOutOfRange(t *testing.T) {
+	le := binary.LittleEndian
+	data := buildTIFF(le, []testEntry{orientationEntry(le, 6), asciiEntry(tagDateTime, "2024:01:02 03:04:05")}, nil)
+
+	// Point the DateTime value (out of line, second entry) past the end of the payload
+	le.PutUint32(data[8+2+12+8:], uint32(len(data)+100))
+	got, err := parseExif(data)
+	if err != nil {
+		t.Fatalf("parseExif() error = %v", err)
+	}
+	assertMetadata(t, got, &ImageMetadata{Orientation: 6})
+
+	// Sub-IFD pointers past the end are ignored as well
+	data = buildTIFF(le, []testEntry{orientationEntry(le, 3)}, map[uint16][]testEntry{
+		tagExifIFD: {asciiEntry(tagDateTimeOriginal, "2023:05:17 14:30:00")},
+	})
+	le.PutUint32(data[8+2+12+8:], 0xFFFFFFF0)
+	got, err = parseExif(data)
+	if err != nil {
+		t.Fatalf("parseExif() error = %v", err)
+	}
+	assertMetadata(t, got, &ImageMetadata{Orientation: 3})
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func assertMetadata(t *testing.T, got, want *ImageMetadata) {
+	t.Helper()
+	if got.Orientation != want.Orientation {
+		t.Errorf("Orientation = %d, want %d", got.Orientation, want.Orientation)
+	}
+	switch {
+	case (got.CapturedAt == nil) != (want.CapturedAt == nil):
+		t.Errorf("CapturedAt = %v, want %v", got.CapturedAt, want.CapturedAt)
+	case got.CapturedAt != nil && !got.CapturedAt.Equal(*want.CapturedAt):
+		t.Errorf("CapturedAt = %v, want %v", *got.CapturedAt, *want.CapturedAt)
+	}
+	assertCoordinate(t, "Latitude", got.Latitude, want.Latitude)
+	assertCoordinate(t, "Longitude", got.Longitude, want.Longitude)
+}
+
+func assertCoordinate(t *testing.T, name string, got, want *float64) {
+	t.Helper()
+	switch {
+	case (got == nil) != (want == nil):
+		t.Errorf("%s = %v, want %v", name, got, want)
+	case got != nil && math.Abs(*got-*want) > 1e-9:
+		t.Errorf("%s = %f, want %f", name, *got, *want)
+	}
+}
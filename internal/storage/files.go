@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+)
+
+const (
+	// Allowed image MIME types
+	allowedImageTypes = "image/jpeg,image/png,image/webp"
+)
+
+// SaveFile validates an uploaded image, stores it in the configured backend and returns the public URL
+func SaveFile(ctx context.Context, fileData []byte, originalFilename string) (string, error) {
+	// Generate unique filename (SHA1 of file data + extension)
+	hash := sha1.Sum(fileData)
+	return SaveFileWithHash(ctx, fileData, originalFilename, hex.EncodeToString(hash[:]))
+}
+
+// SaveFileWithHash is SaveFile for callers that already computed the hex SHA1 of fileData
+// (e.g. while streaming the upload to disk)
+func SaveFileWithHash(ctx context.Context, fileData []byte, originalFilename, hashStr string) (string, error) {
+	// Validate image type
+	contentType := http.DetectContentType(fileData)
+	if !IsAllowedImageType(contentType) {
+		return "", fmt.Errorf("invalid image type: %s. Allowed types: %s", contentType, allowedImageTypes)
+	}
+
+	ext := filepath.Ext(originalFilename)
+	if ext == "" {
+		// Try to determine extension from content type
+		exts, _ := mime.ExtensionsByType(contentType)
+		if len(exts) > 0 {
+			ext = exts[0]
+		} else {
+			ext = ".jpg" // default
+		}
+	}
+
+	key := "img/" + hashStr + ext
+	if err := Store.Put(ctx, key, bytes.NewReader(fileData), int64(len(fileData)), contentType); err != nil {
+		return "", err
+	}
+
+	// Return public URL path
+	return PathForKey(key), nil
+}
+
+// DeleteFile deletes a file from the configured backend
+func DeleteFile(ctx context.Context, url string) error {
+	key, err := KeyFromURL(url)
+	if err != nil {
+		return err
+	}
+
+	return Store.Delete(ctx, key)
+}
+
+// IsAllowedImageType checks if the content type is an allowed image type
+func IsAllowedImageType(contentType string) bool {
+	allowed := []string{"image/jpeg", "image/png", "image/webp"}
+	for _, t := range allowed {
+		if contentType == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/sqlc"
+)
+
+// GCOptions configures a storage garbage collection run
+type GCOptions struct {
+	// DryRun only reports orphan files instead of deleting them
+	DryRun bool
+	// GracePeriod skips files modified more recently than this, so uploads whose
+	// transaction has not committed yet are never treated as orphans
+	GracePeriod time.Duration
+}
+
+// GCReport summarizes a storage garbage collection run
+type GCReport struct {
+	ScannedFiles   int
+	SkippedRecent  int
+	OrphanFiles    []FileInfo
+	DeletedFiles   []string
+	DanglingImages []sqlc.ProjectImage // project_images rows whose file is missing from storage
+	Errors         []error
+}
+
+// CollectGarbage compares the files in storage against the URLs referenced by
+// project_images and project_image_variants. Unreferenced files older than the grace
+// period are deleted (unless DryRun is set); image rows pointing at missing files are reported only
+func CollectGarbage(ctx context.Context, queries *sqlc.Queries, opts GCOptions) (*GCReport, error) {
+	report := &GCReport{}
+
+	// List files before loading references: a file committed in between is then always seen as referenced
+	files, err := Store.List(ctx, "img/")
+	if err != nil {
+		return nil, err
+	}
+	report.ScannedFiles = len(files)
+
+	referencedURLs, err := queries.ListReferencedStorageURLs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referenced URLs: %w", err)
+	}
+	referenced := make(map[string]bool, len(referencedURLs))
+	for _, url := range referencedURLs {
+		referenced[url] = true
+	}
+
+	// Orphan files
+	cutoff := time.Now().Add(-opts.GracePeriod)
+	existing := make(map[string]bool, len(files))
+	for _, file := range files {
+		url := PathForKey(file.Key)
+		existing[url] = true
+
+		if referenced[url] {
+			continue
+		}
+		if file.ModTime.After(cutoff) {
+			report.SkippedRecent++
+			continue
+		}
+
+		report.OrphanFiles = append(report.OrphanFiles, file)
+		if opts.DryRun {
+			continue
+		}
+		// An identical upload may have rewritten the file since it was listed
+		if current, err := Store.Stat(ctx, file.Key); err != nil || current.ModTime.After(cutoff) {
+			report.SkippedRecent++
+			continue
+		}
+		if err := Store.Delete(ctx, file.Key); err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("%s: %w", file.Key, err))
+			continue
+		}
+		report.DeletedFiles = append(report.DeletedFiles, url)
+	}
+
+	// Dangling rows
+	images, err := queries.ListAllProjectImages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project images: %w", err)
+	}
+	for _, img := range images {
+		if !existing[img.Url] {
+			report.DanglingImages = append(report.DanglingImages, img)
+		}
+	}
+
+	return report, nil
+}
+
+// RunGCPeriodically runs CollectGarbage every interval until ctx is cancelled, logging a summary of each run
+func RunGCPeriodically(ctx context.Context, queries *sqlc.Queries, interval time.Duration, opts GCOptions) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := CollectGarbage(ctx, queries, opts)
+			if err != nil {
+				log.Printf("Storage GC failed: %v", err)
+				continue
+			}
+			log.Printf("Storage GC: scanned %d files, %d orphans, %d deleted, %d dangling images, %d errors",
+				report.ScannedFiles, len(report.OrphanFiles), len(report.DeletedFiles), len(report.DanglingImages), len(report.Errors))
+			for _, err := range report.Errors {
+				log.Printf("Storage GC error: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"net/http"
+
+	"github.com/buckket/go-blurhash"
+)
+
+// ImageInfo describes the intrinsic properties of a stored image
+type ImageInfo struct {
+	Width         int
+	Height        int
+	SizeBytes     int64
+	MimeType      string
+	DominantColor string // #rrggbb
+	BlurHash      string
+}
+
+// AnalyzeImage decodes an image once and extracts its dimensions, size, MIME type,
+// dominant color and blurhash
+func AnalyzeImage(fileData []byte) (*ImageInfo, error) {
+	img, _, err := image.Decode(bytes.NewReader(fileData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	info := &ImageInfo{
+		Width:     bounds.Dx(),
+		Height:    bounds.Dy(),
+		SizeBytes: int64(len(fileData)),
+		MimeType:  http.DetectContentType(fileData),
+	}
+
+	// Both the color and the blurhash only need a tiny sample of the image
+	sample := resizeToWidth(img, min(blurHashSampleWidth, info.Width), false).(*image.NRGBA)
+
+	info.DominantColor = dominantColor(sample)
+
+	hash, err := blurhash.Encode(4, 4, sample)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate blurhash: %w", err)
+	}
+	info.BlurHash = hash
+
+	return info, nil
+}
+
+// dominantColor returns the average color of the most common color bucket
+// (4 bits per channel), ignoring mostly transparent pixels
+func dominantColor(img *image.NRGBA) string {
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+	buckets := make(map[int]*bucket)
+	var best *bucket
+
+	for i := 0; i+3 < len(img.Pix); i += 4 {
+		r, g, b, a := int(img.Pix[i]), int(img.Pix[i+1]), int(img.Pix[i+2]), img.Pix[i+3]
+		if a < 128 {
+			continue
+		}
+		idx := (r>>4)<<8 | (g>>4)<<4 | b>>4
+		bk := buckets[idx]
+		if bk == nil {
+			bk = &bucket{}
+			buckets[idx] = bk
+		}
+		bk.count++
+		bk.r += r
+		bk.g += g
+		bk.b += b
+		if best == nil || bk.count > best.count {
+			best = bk
+		}
+	}
+
+	if best == nil {
+		return "#ffffff"
+	}
+	return fmt.Sprintf("#%02x%02x%02x", best.r/best.count, best.g/best.count, best.b/best.count)
+}
@@ -1,91 +1,175 @@
 package storage
 
 import (
-	"crypto/sha1"
-	"encoding/hex"
+	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"mime"
-	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 )
 
-const (
-	// Allowed image MIME types
-	allowedImageTypes = "image/jpeg,image/png,image/webp"
-)
+// LocalBackend stores files on the local filesystem under <StoragePath>/public
+type LocalBackend struct {
+	root string
+}
 
-// SaveFile saves a file to the local filesystem and returns the public URL
-func SaveFile(fileData []byte, originalFilename, storagePath string) (string, error) {
-	// Validate image type
-	contentType := http.DetectContentType(fileData)
-	if !isAllowedImageType(contentType) {
-		return "", fmt.Errorf("invalid image type: %s. Allowed types: %s", contentType, allowedImageTypes)
-	}
-
-	// Generate unique filename (SHA1 of file data + extension)
-	hash := sha1.Sum(fileData)
-	hashStr := hex.EncodeToString(hash[:])
-	
-	ext := filepath.Ext(originalFilename)
-	if ext == "" {
-		// Try to determine extension from content type
-		exts, _ := mime.ExtensionsByType(contentType)
-		if len(exts) > 0 {
-			ext = exts[0]
-		} else {
-			ext = ".jpg" // default
-		}
-	}
+// NewLocalBackend creates a local filesystem backend rooted at <storagePath>/public
+func NewLocalBackend(storagePath string) *LocalBackend {
+	return &LocalBackend{root: filepath.Join(storagePath, "public")}
+}
 
-	filename := hashStr + ext
-	filePath := filepath.Join(storagePath, "public", "img", filename)
+// Put writes a file to the local filesystem
+func (b *LocalBackend) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	filePath, err := b.path(key)
+	if err != nil {
+		return err
+	}
 
 	// Create directory if it doesn't exist
 	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		return "", fmt.Errorf("failed to create directory: %w", err)
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
 	}
+	defer os.Remove(tmp.Name())
 
-	// Write file
-	if err := os.WriteFile(filePath, fileData, 0644); err != nil {
-		return "", fmt.Errorf("failed to write file: %w", err)
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
 	}
 
-	// Return public URL path
-	return fmt.Sprintf("/storage/img/%s", filename), nil
+	return nil
 }
 
-// DeleteFile deletes a file from the local filesystem
-func DeleteFile(url, storagePath string) error {
-	// Extract filename from URL
-	if !strings.HasPrefix(url, "/storage/img/") {
-		return fmt.Errorf("invalid URL format: %s", url)
+// Get opens a file from the local filesystem. The returned reader is an *os.File
+func (b *LocalBackend) Get(ctx context.Context, key string) (io.ReadCloser, *FileInfo, error) {
+	filePath, err := b.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to stat file: %w", err)
 	}
 
-	filename := strings.TrimPrefix(url, "/storage/img/")
-	filePath := filepath.Join(storagePath, "public", "img", filename)
+	return file, localFileInfo(key, stat), nil
+}
 
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil // File doesn't exist, consider it already deleted
+// Delete deletes a file from the local filesystem
+func (b *LocalBackend) Delete(ctx context.Context, key string) error {
+	filePath, err := b.path(key)
+	if err != nil {
+		return err
 	}
 
-	// Delete file
-	if err := os.Remove(filePath); err != nil {
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
 
 	return nil
 }
 
-// isAllowedImageType checks if the content type is an allowed image type
-func isAllowedImageType(contentType string) bool {
-	allowed := []string{"image/jpeg", "image/png", "image/webp"}
-	for _, t := range allowed {
-		if contentType == t {
-			return true
+// Stat returns metadata for a file on the local filesystem
+func (b *LocalBackend) Stat(ctx context.Context, key string) (*FileInfo, error) {
+	filePath, err := b.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	return localFileInfo(key, stat), nil
+}
+
+// List walks the storage root and returns all files whose key starts with prefix
+func (b *LocalBackend) List(ctx context.Context, prefix string) ([]FileInfo, error) {
+	var files []FileInfo
+	err := filepath.WalkDir(b.root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(b.root, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		stat, err := d.Info()
+		if err != nil {
+			return err
 		}
+		files = append(files, *localFileInfo(key, stat))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return files, nil
+}
+
+// URL returns the API path the file is served from
+func (b *LocalBackend) URL(key string) string {
+	return PathForKey(key)
+}
+
+// path resolves a key to a filesystem path, rejecting keys that escape the root
+func (b *LocalBackend) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid storage key: %s", key)
+	}
+	return filepath.Join(b.root, filepath.FromSlash(cleaned)), nil
+}
+
+func localFileInfo(key string, stat os.FileInfo) *FileInfo {
+	return &FileInfo{
+		Key:         key,
+		Size:        stat.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(key)),
+		ModTime:     stat.ModTime(),
 	}
-	return false
 }
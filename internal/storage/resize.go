@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"golang.org/x/image/draw"
+)
+
+// Fit modes for on-the-fly resizing when both width and height are given
+const (
+	FitCover   = "cover"   // fill the box, cropping the overflow around the center
+	FitContain = "contain" // fit inside the box without cropping
+)
+
+// ResizeOptions describes an on-the-fly resize; a zero Width or Height means "auto"
+type ResizeOptions struct {
+	Width  int
+	Height int
+	Fit    string
+	Format string
+}
+
+// CacheName returns the file name a resized copy of the given source key is cached under
+func (o ResizeOptions) CacheName(sourceKey string) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%d|%s|%s", sourceKey, o.Width, o.Height, o.Fit, o.Format)))
+	return hex.EncodeToString(sum[:]) + formatExtensions[o.Format][0]
+}
+
+// ContentType returns the MIME type of the resized output
+func (o ResizeOptions) ContentType() string {
+	return formatExtensions[o.Format][1]
+}
+
+// ErrImageTooLarge is returned by ResizeImage for sources with more pixels than allowed
+var ErrImageTooLarge = errors.New("image too large to resize")
+
+// ResizeImage decodes an image, scales (and for FitCover crops) it as described by opts and
+// encodes it in opts.Format. Images are never upscaled. Sources larger than maxPixels are
+// rejected with ErrImageTooLarge before being decoded
+func ResizeImage(fileData []byte, opts ResizeOptions, imgOpts config.ImageConfig, maxPixels int64) ([]byte, error) {
+	header, _, err := image.DecodeConfig(bytes.NewReader(fileData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if int64(header.Width)*int64(header.Height) > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, header.Width, header.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(fileData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	srcW, srcH := float64(bounds.Dx()), float64(bounds.Dy())
+
+	// Scale factor and source region to draw from
+	var scale float64
+	srcRect := bounds
+	switch {
+	case opts.Height == 0:
+		scale = float64(opts.Width) / srcW
+	case opts.Width == 0:
+		scale = float64(opts.Height) / srcH
+	case opts.Fit == FitContain:
+		scale = math.Min(float64(opts.Width)/srcW, float64(opts.Height)/srcH)
+	default:
+		scale = math.Max(float64(opts.Width)/srcW, float64(opts.Height)/srcH)
+	}
+	scale = math.Min(scale, 1)
+
+	dstW := int(math.Round(srcW * scale))
+	dstH := int(math.Round(srcH * scale))
+
+	// Cover: crop the part of the source that fills the box, centered
+	if opts.Width > 0 && opts.Height > 0 && opts.Fit != FitContain {
+		dstW = min(dstW, opts.Width)
+		dstH = min(dstH, opts.Height)
+		cropW := int(math.Round(float64(dstW) / scale))
+		cropH := int(math.Round(float64(dstH) / scale))
+		x0 := bounds.Min.X + (bounds.Dx()-cropW)/2
+		y0 := bounds.Min.Y + (bounds.Dy()-cropH)/2
+		srcRect = image.Rect(x0, y0, x0+cropW, y0+cropH).Intersect(bounds)
+	}
+
+	dstW, dstH = max(dstW, 1), max(dstH, 1)
+	rect := image.Rect(0, 0, dstW, dstH)
+
+	var dst draw.Image
+	if opts.Format == FormatJPEG {
+		// JPEG has no alpha channel; composite onto white
+		rgba := image.NewRGBA(rect)
+		draw.Draw(rgba, rect, image.White, image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(rgba, rect, img, srcRect, draw.Over, nil)
+		dst = rgba
+	} else {
+		nrgba := image.NewNRGBA(rect)
+		draw.CatmullRom.Scale(nrgba, rect, img, srcRect, draw.Src, nil)
+		dst = nrgba
+	}
+
+	data, err := encodeImage(dst, opts.Format, imgOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return data, nil
+}
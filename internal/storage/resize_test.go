@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// stripedImage returns a 400x200 image whose outer quarters are red (left) and blue (right)
+// with a green middle, so a centered cover crop only keeps green
+func stripedImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			c := color.NRGBA{G: 255, A: 255}
+			switch {
+			case x < 100:
+				c = color.NRGBA{R: 255, A: 255}
+			case x >= 300:
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestResizeImage(t *testing.T) {
+	src := encodePNG(t, stripedImage())
+
+	tests := []struct {
+		name      string
+		opts      ResizeOptions
+		wantSize  image.Point
+		wantColor map[image.Point]color.NRGBA // sampled pixels of the output
+	}{
+		{
+			name:     "width only keeps aspect ratio",
+			opts:     ResizeOptions{Width: 100},
+			wantSize: image.Pt(100, 50),
+		},
+		{
+			name:     "height only keeps aspect ratio",
+			opts:     ResizeOptions{Height: 50},
+			wantSize: image.Pt(100, 50),
+		},
+		{
+			name:     "no upscaling",
+			opts:     ResizeOptions{Width: 800},
+			wantSize: image.Pt(400, 200),
+		},
+		{
+			name:     "tiny width keeps at least one pixel",
+			opts:     ResizeOptions{Width: 1},
+			wantSize: image.Pt(1, 1),
+		},
+		{
+			name:     "contain fits inside the box",
+			opts:     ResizeOptions{Width: 100, Height: 100, Fit: FitContain},
+			wantSize: image.Pt(100, 50),
+			wantColor: map[image.Point]color.NRGBA{
+				{X: 2, Y: 25}:  {R: 255, A: 255},
+				{X: 50, Y: 25}: {G: 255, A: 255},
+				{X: 97, Y: 25}: {B: 255, A: 255},
+			},
+		},
+		{
+			name:     "contain does not upscale",
+			opts:     ResizeOptions{Width: 1000, Height: 1000, Fit: FitContain},
+			wantSize: image.Pt(400, 200),
+		},
+		{
+			name:     "cover fills the box and crops the center",
+			opts:     ResizeOptions{Width: 100, Height: 100, Fit: FitCover},
+			wantSize: image.Pt(100, 100),
+			wantColor: map[image.Point]color.NRGBA{
+				{X: 2, Y: 2}:   {G: 255, A: 255},
+				{X: 97, Y: 97}: {G: 255, A: 255},
+			},
+		},
+		{
+			name:     "cover with wide box crops vertically",
+			opts:     ResizeOptions{Width: 100, Height: 20, Fit: FitCover},
+			wantSize: image.Pt(100, 20),
+			wantColor: map[image.Point]color.NRGBA{
+				{X: 2, Y: 10}:  {R: 255, A: 255},
+				{X: 97, Y: 10}: {B: 255, A: 255},
+			},
+		},
+		{
+			name:     "cover larger than source crops without upscaling",
+			opts:     ResizeOptions{Width: 200, Height: 400, Fit: FitCover},
+			wantSize: image.Pt(200, 200),
+			wantColor: map[image.Point]color.NRGBA{
+				{X: 2, Y: 2}:     {G: 255, A: 255},
+				{X: 197, Y: 197}: {G: 255, A: 255},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Format = FormatPNG
+			out, err := ResizeImage(src, opts, testImageConfig, 400*200)
+			if err != nil {
+				t.Fatalf("ResizeImage() error = %v", err)
+			}
+
+			img, _, err := image.Decode(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("resized image does not decode: %v", err)
+			}
+			if got := img.Bounds().Size(); got != tt.wantSize {
+				t.Errorf("size = %v, want %v", got, tt.wantSize)
+			}
+			for pt, want := range tt.wantColor {
+				if got := color.NRGBAModel.Convert(img.At(pt.X, pt.Y)).(color.NRGBA); got != want {
+					t.Errorf("pixel at %v = %v, want %v", pt, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestResizeImageRejects(t *testing.T) {
+	src := encodePNG(t, stripedImage())
+	opts := ResizeOptions{Width: 100, Format: FormatPNG}
+
+	if _, err := ResizeImage(src, opts, testImageConfig, 400*200-1); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("ResizeImage() over the pixel limit error = %v, want %v", err, ErrImageTooLarge)
+	}
+	if _, err := ResizeImage([]byte("not an image"), opts, testImageConfig, 400*200); err == nil || errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("ResizeImage() on garbage error = %v, want a decode error", err)
+	}
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Backend stores files in an S3-compatible bucket (AWS S3, MinIO, R2, ...)
+type S3Backend struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+// NewS3Backend connects to the configured endpoint and makes sure the bucket exists
+func NewS3Backend(ctx context.Context, cfg config.S3Config) (*S3Backend, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", cfg.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", cfg.Bucket, err)
+		}
+	}
+
+	return &S3Backend{
+		client:    client,
+		bucket:    cfg.Bucket,
+		publicURL: strings.TrimRight(cfg.PublicURL, "/"),
+	}, nil
+}
+
+// Put uploads an object to the bucket
+func (b *S3Backend) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := b.client.PutObject(ctx, b.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+		// Keys are content-addressed, so objects never change once written
+		CacheControl: "public, max-age=31536000, immutable",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	return nil
+}
+
+// Get opens an object from the bucket. The returned reader is a *minio.Object
+func (b *S3Backend) Get(ctx context.Context, key string) (io.ReadCloser, *FileInfo, error) {
+	obj, err := b.client.GetObject(ctx, b.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get object: %w", err)
+	}
+
+	// GetObject is lazy, Stat performs the request and surfaces missing keys
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		if isS3NotFound(err) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get object: %w", err)
+	}
+
+	return obj, s3FileInfo(info), nil
+}
+
+// Delete removes an object from the bucket
+func (b *S3Backend) Delete(ctx context.Context, key string) error {
+	if err := b.client.RemoveObject(ctx, b.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	return nil
+}
+
+// Stat returns metadata for an object in the bucket
+func (b *S3Backend) Stat(ctx context.Context, key string) (*FileInfo, error) {
+	info, err := b.client.StatObject(ctx, b.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to stat object: %w", err)
+	}
+	return s3FileInfo(info), nil
+}
+
+// List returns all objects whose key starts with prefix
+func (b *S3Backend) List(ctx context.Context, prefix string) ([]FileInfo, error) {
+	var files []FileInfo
+	for obj := range b.client.ListObjects(ctx, b.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		files = append(files, *s3FileInfo(obj))
+	}
+	return files, nil
+}
+
+// URL returns the public bucket/CDN URL when S3_PUBLIC_URL is set,
+// otherwise the API path that proxies the object
+func (b *S3Backend) URL(key string) string {
+	if b.publicURL == "" {
+		return PathForKey(key)
+	}
+	return b.publicURL + "/" + key
+}
+
+func isS3NotFound(err error) bool {
+	code := minio.ToErrorResponse(err).Code
+	return code == "NoSuchKey" || code == "NotFound"
+}
+
+func s3FileInfo(info minio.ObjectInfo) *FileInfo {
+	return &FileInfo{
+		Key:         info.Key,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		ModTime:     info.LastModified,
+	}
+}
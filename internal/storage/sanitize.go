@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"github.com/gen2brain/webp"
+	"golang.org/x/image/draw"
+)
+
+var errInvalidImage = errors.New("malformed image file")
+
+// SanitizeImage applies the EXIF orientation to an uploaded image and strips all metadata
+// (EXIF incl. GPS, XMP, IPTC, comments) so it never reaches storage. It returns the cleaned
+// file together with the metadata that was found. Files of unknown type are returned as-is
+func SanitizeImage(fileData []byte, opts config.ImageConfig) ([]byte, *ImageMetadata, error) {
+	var cleaned, exif []byte
+	var err error
+
+	contentType := http.DetectContentType(fileData)
+	switch contentType {
+	case "image/jpeg":
+		cleaned, exif, err = stripJPEG(fileData)
+	case "image/png":
+		cleaned, exif, err = stripPNG(fileData)
+	case "image/webp":
+		cleaned, exif, err = stripWebP(fileData)
+	default:
+		return fileData, &ImageMetadata{}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &ImageMetadata{}
+	if exif != nil {
+		// Unreadable EXIF is dropped like any other metadata
+		if parsed, err := parseExif(bytes.TrimPrefix(exif, []byte("Exif\x00\x00"))); err == nil {
+			meta = parsed
+		}
+	}
+
+	if meta.Orientation < 2 || meta.Orientation > 8 {
+		return cleaned, meta, nil
+	}
+
+	// Bake the orientation into the pixels; re-encoding also drops anything we kept (e.g. ICC profiles)
+	img, _, err := image.Decode(bytes.NewReader(cleaned))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	oriented := applyOrientation(img, meta.Orientation)
+
+	var buf bytes.Buffer
+	switch contentType {
+	case "image/png":
+		err = png.Encode(&buf, oriented)
+	case "image/webp":
+		err = webp.Encode(&buf, oriented, webp.Options{Quality: opts.WebPQuality})
+	default:
+		err = jpeg.Encode(&buf, oriented, &jpeg.Options{Quality: opts.JPEGQuality})
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	return buf.Bytes(), meta, nil
+}
+
+// stripJPEG removes metadata segments from a JPEG stream without re-encoding it.
+// JFIF (APP0), ICC profiles (APP2) and Adobe color info (APP14) are kept since they affect rendering.
+// The EXIF payload (if any) is returned separately
+func stripJPEG(data []byte) ([]byte, []byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, nil, errInvalidImage
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+
+	var exif []byte
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, nil, errInvalidImage
+		}
+		marker := data[pos+1]
+
+		// Fill bytes and standalone markers carry no length
+		if marker == 0xFF {
+			pos++
+			continue
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out.Write(data[pos : pos+2])
+			pos += 2
+			continue
+		}
+
+		// Start of scan: the rest is entropy-coded data
+		if marker == 0xDA || marker == 0xD9 {
+			out.Write(data[pos:])
+			return out.Bytes(), exif, nil
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, nil, errInvalidImage
+		}
+		segment := data[pos:end]
+
+		switch {
+		case marker == 0xE1: // APP1: EXIF or XMP
+			payload := segment[4:]
+			if bytes.HasPrefix(payload, []byte("Exif\x00\x00")) && exif == nil {
+				exif = payload
+			}
+		case marker >= 0xE3 && marker <= 0xED, marker == 0xEF, marker == 0xFE:
+			// APP3-APP13 (vendor data, IPTC), APP15 and comments
+		default:
+			out.Write(segment)
+		}
+		pos = end
+	}
+
+	return nil, nil, errInvalidImage
+}
+
+// pngSignature is the 8-byte header of every PNG file
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// stripPNG removes textual, time and EXIF chunks from a PNG file. The eXIf payload (if any) is returned separately
+func stripPNG(data []byte) ([]byte, []byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, nil, errInvalidImage
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+
+	var exif []byte
+	pos := len(pngSignature)
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, nil, errInvalidImage
+		}
+		chunkType := string(data[pos+4 : pos+8])
+
+		switch chunkType {
+		case "eXIf":
+			exif = data[pos+8 : pos+8+length]
+		case "tEXt", "zTXt", "iTXt", "tIME":
+		default:
+			out.Write(data[pos:end])
+		}
+
+		pos = end
+		if chunkType == "IEND" {
+			return out.Bytes(), exif, nil
+		}
+	}
+
+	return nil, nil, errInvalidImage
+}
+
+// VP8X feature flags describing which metadata chunks are present
+const (
+	webpFlagXMP  = 0x04
+	webpFlagEXIF = 0x08
+)
+
+// stripWebP removes EXIF and XMP chunks from a WebP file. The EXIF payload (if any) is returned separately
+func stripWebP(data []byte) ([]byte, []byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, nil, errInvalidImage
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:12])
+
+	var exif []byte
+	pos := 12
+	for pos+8 <= len(data) {
+		fourCC := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := pos + 8 + size + size%2 // chunks are padded to an even size
+		if size < 0 || pos+8+size > len(data) {
+			return nil, nil, errInvalidImage
+		}
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := data[pos:end]
+
+		switch fourCC {
+		case "EXIF":
+			exif = data[pos+8 : pos+8+size]
+		case "XMP ":
+		case "VP8X":
+			chunk = append([]byte(nil), chunk...)
+			if len(chunk) > 8 {
+				chunk[8] &^= webpFlagEXIF | webpFlagXMP
+			}
+			out.Write(chunk)
+		default:
+			out.Write(chunk)
+		}
+		pos = end
+	}
+
+	cleaned := out.Bytes()
+	binary.LittleEndian.PutUint32(cleaned[4:8], uint32(len(cleaned)-8))
+	return cleaned, exif, nil
+}
+
+// applyOrientation transforms img so that it displays upright for the given EXIF orientation (2-8)
+func applyOrientation(img image.Image, orientation int) image.Image {
+	bounds := img.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		// Orientations 5-8 swap the axes
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirror horizontal
+				dx, dy = w-1-x, y
+			case 3: // rotate 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirror vertical
+				dx, dy = x, h-1-y
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // rotate 90 CW
+				dx, dy = h-1-y, x
+			case 7: // transverse
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotate 90 CCW
+				dx, dy = y, w-1-x
+			}
+			si := src.PixOffset(x, y)
+			di := dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+
+	return dst
+}
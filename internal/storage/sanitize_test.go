@@ -0,0 +1,351 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+)
+
+var testImageConfig = config.ImageConfig{JPEGQuality: 90, WebPQuality: 80}
+
+// testImage returns a w x h image with a red pixel at the origin and white everywhere else
+func testImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// jpegSegment encodes a JPEG marker segment with the given payload
+func jpegSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// withJPEGSegments inserts segments right after the SOI marker
+func withJPEGSegments(data []byte, segments ...[]byte) []byte {
+	out := append([]byte(nil), data[:2]...)
+	for _, s := range segments {
+		out = append(out, s...)
+	}
+	return append(out, data[2:]...)
+}
+
+// pngChunk encodes a PNG chunk including its CRC
+func pngChunk(chunkType string, payload []byte) []byte {
+	chunk := make([]byte, 8, 12+len(payload))
+	binary.BigEndian.PutUint32(chunk, uint32(len(payload)))
+	copy(chunk[4:], chunkType)
+	chunk = append(chunk, payload...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// withPNGChunks inserts chunks right after IHDR
+func withPNGChunks(data []byte, chunks ...[]byte) []byte {
+	ihdrEnd := len(pngSignature) + 12 + 13
+	out := append([]byte(nil), data[:ihdrEnd]...)
+	for _, c := range chunks {
+		out = append(out, c...)
+	}
+	return append(out, data[ihdrEnd:]...)
+}
+
+// exifPayload returns an APP1/eXIf payload carrying the given orientation
+func exifPayload(orientation uint16, withHeader bool) []byte {
+	le := binary.LittleEndian
+	tiff := buildTIFF(le, []testEntry{
+		orientationEntry(le, orientation),
+		asciiEntry(tagDateTime, "2024:01:02 03:04:05"),
+	}, map[uint16][]testEntry{tagGPSIFD: {
+		asciiEntry(tagGPSLatitudeRef, "N"),
+		rationalEntry(le, tagGPSLatitude, 44, 1, 48, 1, 0, 1),
+	}})
+	if !withHeader {
+		return tiff
+	}
+	return append([]byte("Exif\x00\x00"), tiff...)
+}
+
+func TestSanitizeImage(t *testing.T) {
+	jpg := encodeJPEG(t, testImage(4, 2))
+	pngData := encodePNG(t, testImage(4, 2))
+	comment := []byte("secret comment")
+
+	tests := []struct {
+		name            string
+		data            []byte
+		wantErr         bool
+		wantOrientation int
+		wantCaptured    bool
+		wantLatitude    bool
+		wantSize        image.Point
+		wantRedAt       *image.Point // checked on lossless output only
+	}{
+		{
+			name:     "plain jpeg",
+			data:     jpg,
+			wantSize: image.Pt(4, 2),
+		},
+		{
+			name: "jpeg exif comment and iptc stripped",
+			data: withJPEGSegments(jpg,
+				jpegSegment(0xE1, exifPayload(1, true)),
+				jpegSegment(0xFE, comment),
+				jpegSegment(0xED, comment),
+			),
+			wantOrientation: 1,
+			wantCaptured:    true,
+			wantLatitude:    true,
+			wantSize:        image.Pt(4, 2),
+		},
+		{
+			name:            "jpeg rotated by orientation",
+			data:            withJPEGSegments(jpg, jpegSegment(0xE1, exifPayload(6, true))),
+			wantOrientation: 6,
+			wantCaptured:    true,
+			wantLatitude:    true,
+			wantSize:        image.Pt(2, 4),
+		},
+		{
+			name:     "jpeg malformed exif dropped",
+			data:     withJPEGSegments(jpg, jpegSegment(0xE1, []byte("Exif\x00\x00II*\x00\xff\xff"))),
+			wantSize: image.Pt(4, 2),
+		},
+		{
+			name:    "jpeg truncated segment",
+			data:    append(append([]byte(nil), jpg[:2]...), 0xFF, 0xE1, 0x10, 0x00, 'E', 'x'),
+			wantErr: true,
+		},
+		{
+			name:    "jpeg segment length too small",
+			data:    withJPEGSegments(jpg, []byte{0xFF, 0xFE, 0x00, 0x01}),
+			wantErr: true,
+		},
+		{
+			name:    "jpeg missing marker",
+			data:    withJPEGSegments(jpg, jpegSegment(0xFE, comment), []byte{0x00, 0x00, 0x00, 0x00}),
+			wantErr: true,
+		},
+		{
+			name:    "jpeg without scan",
+			data:    withJPEGSegments([]byte{0xFF, 0xD8}, jpegSegment(0xFE, comment)),
+			wantErr: true,
+		},
+		{
+			name: "png text and exif stripped",
+			data: withPNGChunks(pngData,
+				pngChunk("tEXt", append([]byte("Comment\x00"), comment...)),
+				pngChunk("eXIf", exifPayload(1, false)),
+			),
+			wantOrientation: 1,
+			wantCaptured:    true,
+			wantLatitude:    true,
+			wantSize:        image.Pt(4, 2),
+			wantRedAt:       &image.Point{},
+		},
+		{
+			name:            "png rotated by orientation",
+			data:            withPNGChunks(pngData, pngChunk("eXIf", exifPayload(6, false))),
+			wantOrientation: 6,
+			wantCaptured:    true,
+			wantLatitude:    true,
+			wantSize:        image.Pt(2, 4),
+			wantRedAt:       &image.Point{X: 1},
+		},
+		{
+			name:            "png mirrored by orientation",
+			data:            withPNGChunks(pngData, pngChunk("eXIf", exifPayload(2, false))),
+			wantOrientation: 2,
+			wantCaptured:    true,
+			wantLatitude:    true,
+			wantSize:        image.Pt(4, 2),
+			wantRedAt:       &image.Point{X: 3},
+		},
+		{
+			name:     "png malformed exif dropped",
+			data:     withPNGChunks(pngData, pngChunk("eXIf", []byte("MM\x00"))),
+			wantSize: image.Pt(4, 2),
+		},
+		{
+			name:    "png truncated chunk",
+			data:    append(withPNGChunks(pngData[:len(pngData)-12]), pngChunk("tEXt", comment)[:10]...),
+			wantErr: true,
+		},
+		{
+			name:    "png chunk length past end",
+			data:    withPNGChunks(pngData, []byte{0x7F, 0xFF, 0xFF, 0xFF, 't', 'E', 'X', 't', 0, 0, 0, 0}),
+			wantErr: true,
+		},
+		{
+			name:    "png without iend",
+			data:    pngData[:len(pngData)-12],
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleaned, meta, err := SanitizeImage(tt.data, testImageConfig)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SanitizeImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if meta.Orientation != tt.wantOrientation {
+				t.Errorf("Orientation = %d, want %d", meta.Orientation, tt.wantOrientation)
+			}
+			if (meta.CapturedAt != nil) != tt.wantCaptured {
+				t.Errorf("CapturedAt = %v, want set %v", meta.CapturedAt, tt.wantCaptured)
+			}
+			if (meta.Latitude != nil) != tt.wantLatitude {
+				t.Errorf("Latitude = %v, want set %v", meta.Latitude, tt.wantLatitude)
+			}
+
+			for _, leaked := range [][]byte{[]byte("Exif"), []byte("eXIf"), []byte("tEXt"), comment} {
+				if bytes.Contains(cleaned, leaked) {
+					t.Errorf("cleaned image still contains %q", leaked)
+				}
+			}
+
+			img, _, err := image.Decode(bytes.NewReader(cleaned))
+			if err != nil {
+				t.Fatalf("cleaned image does not decode: %v", err)
+			}
+			if got := img.Bounds().Size(); got != tt.wantSize {
+				t.Errorf("size = %v, want %v", got, tt.wantSize)
+			}
+			if tt.wantRedAt != nil {
+				r, g, _, _ := img.At(tt.wantRedAt.X, tt.wantRedAt.Y).RGBA()
+				if r != 0xFFFF || g != 0 {
+					t.Errorf("pixel at %v is not red after orientation", *tt.wantRedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestSanitizeImageUnknownType(t *testing.T) {
+	data := []byte("GIF89a not really an image")
+	cleaned, meta, err := SanitizeImage(data, testImageConfig)
+	if err != nil {
+		t.Fatalf("SanitizeImage() error = %v", err)
+	}
+	if !bytes.Equal(cleaned, data) {
+		t.Errorf("SanitizeImage() changed a file of unknown type")
+	}
+	if meta == nil || meta.Orientation != 0 {
+		t.Errorf("SanitizeImage() metadata = %+v, want empty", meta)
+	}
+}
+
+func TestStripWebP(t *testing.T) {
+	riff := func(chunks ...[]byte) []byte {
+		out := []byte("RIFF\x00\x00\x00\x00WEBP")
+		for _, c := range chunks {
+			out = append(out, c...)
+		}
+		binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+		return out
+	}
+	chunk := func(fourCC string, payload []byte) []byte {
+		out := binary.LittleEndian.AppendUint32([]byte(fourCC), uint32(len(payload)))
+		out = append(out, payload...)
+		if len(payload)%2 == 1 {
+			out = append(out, 0)
+		}
+		return out
+	}
+
+	vp8x := chunk("VP8X", []byte{webpFlagEXIF | webpFlagXMP, 0, 0, 0, 3, 0, 0, 1, 0, 0})
+	vp8l := chunk("VP8L", []byte{0x2F, 1, 2})
+	exif := exifPayload(3, false)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		want     []byte
+		wantExif []byte
+		wantErr  bool
+	}{
+		{
+			name:     "exif and xmp removed",
+			data:     riff(vp8x, vp8l, chunk("EXIF", exif), chunk("XMP ", []byte("<x/>"))),
+			want:     riff(chunk("VP8X", []byte{0, 0, 0, 0, 3, 0, 0, 1, 0, 0}), vp8l),
+			wantExif: exif,
+		},
+		{
+			name: "no metadata",
+			data: riff(vp8l),
+			want: riff(vp8l),
+		},
+		{
+			name:    "truncated chunk",
+			data:    riff(vp8l, []byte("EXIF\xff\x00\x00\x00II")),
+			wantErr: true,
+		},
+		{
+			name:    "not a webp",
+			data:    []byte("RIFF\x04\x00\x00\x00WAVE"),
+			wantErr: true,
+		},
+		{
+			name:    "too short",
+			data:    []byte("RIFF"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotExif, err := stripWebP(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("stripWebP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, errInvalidImage) {
+					t.Errorf("stripWebP() error = %v, want %v", err, errInvalidImage)
+				}
+				return
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("stripWebP() = %q, want %q", got, tt.want)
+			}
+			if !bytes.Equal(gotExif, tt.wantExif) {
+				t.Errorf("stripWebP() exif = %q, want %q", gotExif, tt.wantExif)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/dev-cyprium/elite-constructions-be-v2/internal/config"
+	"github.com/gen2brain/webp"
+	"golang.org/x/image/draw"
+)
+
+// Image formats variants are encoded in
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+)
+
+// formatExtensions maps a variant format to its file extension and content type
+var formatExtensions = map[string][2]string{
+	FormatJPEG: {".jpg", "image/jpeg"},
+	FormatPNG:  {".png", "image/png"},
+	FormatWebP: {".webp", "image/webp"},
+}
+
+// Variant is a derived copy of a stored image (downscaled and/or transcoded)
+type Variant struct {
+	Width  int
+	Height int
+	Format string
+	URL    string
+}
+
+// GenerateVariants stores a downscaled copy of an uploaded image for every configured width
+// smaller than the original, as <sha1>_w<width><ext>. PNG uploads keep PNG variants, everything
+// else gets JPEG variants. When WebP is enabled, every variant is also written as WebP, and a
+// full-size WebP copy of the original is stored as <sha1>.webp
+func GenerateVariants(ctx context.Context, fileData []byte, originalURL string, opts config.ImageConfig) ([]Variant, error) {
+	key, err := KeyFromURL(originalURL)
+	if err != nil {
+		return nil, err
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(fileData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	// PNG keeps transparency, everything else (including WebP uploads) falls back to JPEG
+	fallback := FormatJPEG
+	if format == FormatPNG {
+		fallback = FormatPNG
+	}
+	base := strings.TrimSuffix(key, path.Ext(key))
+
+	var variants []Variant
+	for _, width := range opts.VariantWidths {
+		// Never upscale
+		if width >= img.Bounds().Dx() {
+			continue
+		}
+
+		resized := resizeToWidth(img, width, fallback == FormatJPEG)
+		variantBase := fmt.Sprintf("%s_w%d", base, width)
+
+		variant, err := storeVariant(ctx, variantBase, resized, fallback, opts)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+
+		if opts.WebP {
+			variant, err := storeVariant(ctx, variantBase, resized, FormatWebP, opts)
+			if err != nil {
+				return nil, err
+			}
+			variants = append(variants, variant)
+		}
+	}
+
+	// Full-size modern-format copy of the original
+	if opts.WebP && format != FormatWebP {
+		variant, err := storeVariant(ctx, base, img, FormatWebP, opts)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+
+	return variants, nil
+}
+
+// storeVariant encodes img in the given format and stores it as <base><ext>
+func storeVariant(ctx context.Context, base string, img image.Image, format string, opts config.ImageConfig) (Variant, error) {
+	width := img.Bounds().Dx()
+
+	data, err := encodeImage(img, format, opts)
+	if err != nil {
+		return Variant{}, fmt.Errorf("failed to encode %dpx %s variant: %w", width, format, err)
+	}
+
+	ext := formatExtensions[format]
+	key := base + ext[0]
+	if err := Store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), ext[1]); err != nil {
+		return Variant{}, fmt.Errorf("failed to store %dpx %s variant: %w", width, format, err)
+	}
+
+	return Variant{
+		Width:  width,
+		Height: img.Bounds().Dy(),
+		Format: format,
+		URL:    PathForKey(key),
+	}, nil
+}
+
+// encodeImage encodes img in the given format with the configured quality
+func encodeImage(img image.Image, format string, opts config.ImageConfig) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatPNG:
+		err = png.Encode(&buf, img)
+	case FormatWebP:
+		err = webp.Encode(&buf, img, webp.Options{Quality: opts.WebPQuality})
+	default:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: opts.JPEGQuality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resizeToWidth scales img to the given width, preserving the aspect ratio.
+// When flatten is set, transparent pixels are composited onto white (for JPEG output)
+func resizeToWidth(img image.Image, width int, flatten bool) image.Image {
+	bounds := img.Bounds()
+	height := int(math.Round(float64(bounds.Dy()) * float64(width) / float64(bounds.Dx())))
+	if height < 1 {
+		height = 1
+	}
+	rect := image.Rect(0, 0, width, height)
+
+	if flatten {
+		dst := image.NewRGBA(rect)
+		draw.Draw(dst, rect, image.White, image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(dst, rect, img, bounds, draw.Over, nil)
+		return dst
+	}
+
+	dst := image.NewNRGBA(rect)
+	draw.CatmullRom.Scale(dst, rect, img, bounds, draw.Src, nil)
+	return dst
+}
+
+// AlternateKey returns the key of the copy of an original image stored in the given format
+// (img/abc.jpg -> img/abc.webp)
+func AlternateKey(key, format string) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + formatExtensions[format][0]
+}
@@ -0,0 +1,12 @@
+-- Responsive width variants generated for every uploaded project image
+CREATE TABLE project_image_variants (
+    id BIGSERIAL PRIMARY KEY,
+    project_image_id BIGINT NOT NULL REFERENCES project_images(id) ON DELETE CASCADE,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    url VARCHAR(500) NOT NULL, -- /storage/img/<sha1>_w<width>.jpg
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_project_image_variants_image_width ON project_image_variants(project_image_id, width);
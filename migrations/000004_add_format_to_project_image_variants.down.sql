@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_project_image_variants_image_width_format;
+
+DELETE FROM project_image_variants WHERE format = 'webp';
+
+CREATE UNIQUE INDEX idx_project_image_variants_image_width ON project_image_variants(project_image_id, width);
+
+ALTER TABLE project_image_variants DROP COLUMN IF EXISTS format;
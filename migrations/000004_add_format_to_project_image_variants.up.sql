@@ -0,0 +1,7 @@
+-- Variants can now be stored in several formats (original fallback format + WebP)
+ALTER TABLE project_image_variants ADD COLUMN format VARCHAR(10) NOT NULL DEFAULT 'jpeg';
+
+UPDATE project_image_variants SET format = 'png' WHERE url LIKE '%.png';
+
+DROP INDEX IF EXISTS idx_project_image_variants_image_width;
+CREATE UNIQUE INDEX idx_project_image_variants_image_width_format ON project_image_variants(project_image_id, width, format);
@@ -0,0 +1,3 @@
+ALTER TABLE project_images DROP COLUMN IF EXISTS gps_longitude;
+ALTER TABLE project_images DROP COLUMN IF EXISTS gps_latitude;
+ALTER TABLE project_images DROP COLUMN IF EXISTS captured_at;
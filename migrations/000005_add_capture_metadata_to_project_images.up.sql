@@ -0,0 +1,4 @@
+-- Capture metadata extracted from EXIF before it is stripped (opt-in, internal use only)
+ALTER TABLE project_images ADD COLUMN captured_at TIMESTAMP;
+ALTER TABLE project_images ADD COLUMN gps_latitude DOUBLE PRECISION;
+ALTER TABLE project_images ADD COLUMN gps_longitude DOUBLE PRECISION;
@@ -0,0 +1,13 @@
+-- Content-addressed files with reference counts, so a file shared by several
+-- project images is only removed from storage once the last reference is gone
+CREATE TABLE stored_blobs (
+    id BIGSERIAL PRIMARY KEY,
+    url VARCHAR(500) UNIQUE NOT NULL, -- /storage/img/<sha1>.jpg
+    ref_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Backfill references from existing images
+INSERT INTO stored_blobs (url, ref_count)
+SELECT url, COUNT(*) FROM project_images GROUP BY url;
@@ -0,0 +1,3 @@
+UPDATE project_images
+SET blur_hash = 'data:text/plain;base64,' || encode(convert_to(blur_hash, 'UTF8'), 'base64')
+WHERE blur_hash IS NOT NULL AND blur_hash NOT LIKE 'data:%';
@@ -0,0 +1,4 @@
+-- Blurhashes used to be stored as base64 text data URLs; store the raw blurhash instead
+UPDATE project_images
+SET blur_hash = convert_from(decode(substring(blur_hash FROM 24), 'base64'), 'UTF8')
+WHERE blur_hash LIKE 'data:text/plain;base64,%';
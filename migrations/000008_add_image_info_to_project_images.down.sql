@@ -0,0 +1,5 @@
+ALTER TABLE project_images DROP COLUMN IF EXISTS dominant_color;
+ALTER TABLE project_images DROP COLUMN IF EXISTS mime_type;
+ALTER TABLE project_images DROP COLUMN IF EXISTS size_bytes;
+ALTER TABLE project_images DROP COLUMN IF EXISTS height;
+ALTER TABLE project_images DROP COLUMN IF EXISTS width;
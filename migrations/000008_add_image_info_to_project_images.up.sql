@@ -0,0 +1,6 @@
+-- Intrinsic image properties, recorded on upload so clients can reserve layout space
+ALTER TABLE project_images ADD COLUMN width INTEGER;
+ALTER TABLE project_images ADD COLUMN height INTEGER;
+ALTER TABLE project_images ADD COLUMN size_bytes BIGINT;
+ALTER TABLE project_images ADD COLUMN mime_type VARCHAR(50);
+ALTER TABLE project_images ADD COLUMN dominant_color VARCHAR(7);
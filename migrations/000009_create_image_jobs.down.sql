@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS image_jobs;
+ALTER TABLE project_images DROP COLUMN IF EXISTS processing_error;
+ALTER TABLE project_images DROP COLUMN IF EXISTS processing_status;
@@ -0,0 +1,19 @@
+-- Processing state of each image: blurhash, image info and variants are filled in by background workers
+ALTER TABLE project_images ADD COLUMN processing_status VARCHAR(20) NOT NULL DEFAULT 'ready'; -- processing, ready, failed
+ALTER TABLE project_images ADD COLUMN processing_error TEXT;
+
+-- Queue of pending image processing jobs
+CREATE TABLE image_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    project_image_id BIGINT NOT NULL REFERENCES project_images(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, running, failed
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    run_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_image_jobs_status_run_at ON image_jobs(status, run_at);
+CREATE INDEX idx_image_jobs_project_image_id ON image_jobs(project_image_id);
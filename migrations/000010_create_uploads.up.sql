@@ -0,0 +1,14 @@
+-- Resumable chunked uploads; chunks are appended to a file under <STORAGE_PATH>/uploads until finalized
+CREATE TABLE uploads (
+    id VARCHAR(32) PRIMARY KEY, -- random hex token
+    filename VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    received BIGINT NOT NULL DEFAULT 0,
+    sha1 VARCHAR(40),
+    status VARCHAR(20) NOT NULL DEFAULT 'uploading', -- uploading, complete
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_uploads_expires_at ON uploads(expires_at);
@@ -0,0 +1,2 @@
+ALTER TABLE project_images DROP COLUMN IF EXISTS caption;
+ALTER TABLE project_images DROP COLUMN IF EXISTS alt_text;
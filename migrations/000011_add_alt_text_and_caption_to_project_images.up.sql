@@ -0,0 +1,3 @@
+-- Accessibility text and an optional caption, edited by admins (name stays the uploaded filename)
+ALTER TABLE project_images ADD COLUMN alt_text VARCHAR(255);
+ALTER TABLE project_images ADD COLUMN caption TEXT;
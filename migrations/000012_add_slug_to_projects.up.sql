@@ -0,0 +1,36 @@
+-- URL slug per project, generated from the name (see internal/slug) and editable by admins
+ALTER TABLE projects ADD COLUMN slug VARCHAR(255);
+
+-- Backfill existing projects with the same transliteration as slug.Make
+UPDATE projects
+SET slug = rtrim(left(trim(BOTH '-' FROM regexp_replace(lower(translate(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(name, 'đ', 'dj'), 'Đ', 'dj'), 'ђ', 'dj'), 'Ђ', 'dj'), 'љ', 'lj'), 'Љ', 'lj'), 'њ', 'nj'), 'Њ', 'nj'), 'џ', 'dz'), 'Џ', 'dz'),
+        'čČćĆžŽšŠаАбБвВгГдДеЕжЖзЗиИјЈкКлЛмМнНоОпПрРсСтТћЋуУфФхХцЦчЧшШáÁàÀâÂäÄãÃéÉèÈêÊëËíÍìÌîÎïÏóÓòÒôÔöÖõÕúÚùÙûÛüÜñÑçÇýÝ',
+        'cccczzssaabbvvggddeezzzziijjkkllmmnnoopprrssttccuuffhhccccssaaaaaaaaaaeeeeeeeeiiiiiiiioooooooooouuuuuuuunnccyy')), '[^a-z0-9]+', '-', 'g')), 200), '-');
+
+UPDATE projects SET slug = 'project-' || id WHERE slug = '';
+
+-- Projects sharing a slug get their ID appended, except the oldest one. The result can itself
+-- be taken ("Kuća 3" next to a second "Kuća" with ID 3, "Project 5" next to an unnamed project 5),
+-- so repeat until every slug is unique; each pass makes the colliding slugs longer
+DO $$
+BEGIN
+    LOOP
+        UPDATE projects p
+        SET slug = p.slug || '-' || p.id
+        FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY id) AS n FROM projects) d
+        WHERE d.id = p.id AND d.n > 1;
+        EXIT WHEN NOT FOUND;
+    END LOOP;
+END $$;
+
+ALTER TABLE projects ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX idx_projects_slug ON projects(slug);
+
+-- Previous slugs of renamed projects, so old URLs keep working
+CREATE TABLE project_slug_redirects (
+    slug VARCHAR(255) PRIMARY KEY,
+    project_id BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_project_slug_redirects_project_id ON project_slug_redirects(project_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_projects_status;
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_status_check;
+ALTER TABLE projects ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE projects ALTER COLUMN status TYPE SMALLINT
+    USING CASE status WHEN 'draft' THEN 0 WHEN 'archived' THEN 2 ELSE 1 END;
+ALTER TABLE projects ALTER COLUMN status SET DEFAULT 1;
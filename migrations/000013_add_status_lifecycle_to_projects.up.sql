@@ -0,0 +1,9 @@
+-- Project lifecycle: only published projects are visible on the public site.
+-- Every existing project was live (status was always 1), so they become published.
+ALTER TABLE projects ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE projects ALTER COLUMN status TYPE VARCHAR(20)
+    USING CASE status WHEN 0 THEN 'draft' WHEN 2 THEN 'archived' ELSE 'published' END;
+ALTER TABLE projects ALTER COLUMN status SET DEFAULT 'draft';
+ALTER TABLE projects ADD CONSTRAINT projects_status_check CHECK (status IN ('draft', 'published', 'archived'));
+
+CREATE INDEX idx_projects_status ON projects(status);
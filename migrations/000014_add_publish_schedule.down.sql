@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS publication_events;
+DROP INDEX IF EXISTS idx_testimonials_unpublish_at;
+DROP INDEX IF EXISTS idx_testimonials_publish_at;
+DROP INDEX IF EXISTS idx_projects_unpublish_at;
+DROP INDEX IF EXISTS idx_projects_publish_at;
+ALTER TABLE testimonials DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE testimonials DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS publish_at;
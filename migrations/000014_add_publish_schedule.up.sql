@@ -0,0 +1,26 @@
+-- Optional publishing window; public queries only return rows inside it.
+-- TIMESTAMPTZ so comparisons with NOW() do not depend on the session time zone.
+ALTER TABLE projects ADD COLUMN publish_at TIMESTAMPTZ;
+ALTER TABLE projects ADD COLUMN unpublish_at TIMESTAMPTZ;
+ALTER TABLE testimonials ADD COLUMN publish_at TIMESTAMPTZ;
+ALTER TABLE testimonials ADD COLUMN unpublish_at TIMESTAMPTZ;
+
+CREATE INDEX idx_projects_publish_at ON projects(publish_at) WHERE publish_at IS NOT NULL;
+CREATE INDEX idx_projects_unpublish_at ON projects(unpublish_at) WHERE unpublish_at IS NOT NULL;
+CREATE INDEX idx_testimonials_publish_at ON testimonials(publish_at) WHERE publish_at IS NOT NULL;
+CREATE INDEX idx_testimonials_unpublish_at ON testimonials(unpublish_at) WHERE unpublish_at IS NOT NULL;
+
+-- Audit trail of status changes made by the publishing scheduler
+-- (no foreign key, so the history survives deleting the project or testimonial)
+CREATE TABLE publication_events (
+    id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL, -- project, testimonial
+    entity_id BIGINT NOT NULL,
+    action VARCHAR(20) NOT NULL, -- published, unpublished
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    scheduled_at TIMESTAMPTZ NOT NULL, -- the publish_at/unpublish_at that triggered the change
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_publication_events_entity ON publication_events(entity_type, entity_id);
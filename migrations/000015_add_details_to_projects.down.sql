@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_projects_services;
+DROP INDEX IF EXISTS idx_projects_completion_year;
+ALTER TABLE projects DROP COLUMN IF EXISTS services;
+ALTER TABLE projects DROP COLUMN IF EXISTS duration_months;
+ALTER TABLE projects DROP COLUMN IF EXISTS area_m2;
+ALTER TABLE projects DROP COLUMN IF EXISTS completion_year;
+ALTER TABLE projects DROP COLUMN IF EXISTS location;
+ALTER TABLE projects DROP COLUMN IF EXISTS description;
@@ -0,0 +1,10 @@
+-- Project details shown on the public site
+ALTER TABLE projects ADD COLUMN description TEXT;
+ALTER TABLE projects ADD COLUMN location VARCHAR(255); -- city or address
+ALTER TABLE projects ADD COLUMN completion_year INTEGER;
+ALTER TABLE projects ADD COLUMN area_m2 DOUBLE PRECISION; -- built area in square meters
+ALTER TABLE projects ADD COLUMN duration_months INTEGER;
+ALTER TABLE projects ADD COLUMN services TEXT[] NOT NULL DEFAULT '{}'; -- services performed, e.g. {"Design","Construction"}
+
+CREATE INDEX idx_projects_completion_year ON projects(completion_year);
+CREATE INDEX idx_projects_services ON projects USING GIN (services);
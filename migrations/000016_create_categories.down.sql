@@ -0,0 +1,10 @@
+ALTER TABLE projects ADD COLUMN category VARCHAR(255);
+
+UPDATE projects p
+SET category = c.name
+FROM categories c
+WHERE c.id = p.category_id;
+
+DROP INDEX IF EXISTS idx_projects_category_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,70 @@
+-- Managed project categories, replacing the free-text projects.category column
+CREATE TABLE categories (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    "order" INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_categories_slug ON categories(slug);
+
+ALTER TABLE projects ADD COLUMN category_id BIGINT REFERENCES categories(id) ON DELETE SET NULL;
+CREATE INDEX idx_projects_category_id ON projects(category_id);
+
+-- Same transliteration as slug.Make (see 000012_add_slug_to_projects)
+CREATE FUNCTION pg_temp.category_slug(value TEXT) RETURNS TEXT LANGUAGE sql IMMUTABLE AS $$
+    SELECT rtrim(left(trim(BOTH '-' FROM regexp_replace(lower(translate(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(value, 'đ', 'dj'), 'Đ', 'dj'), 'ђ', 'dj'), 'Ђ', 'dj'), 'љ', 'lj'), 'Љ', 'lj'), 'њ', 'nj'), 'Њ', 'nj'), 'џ', 'dz'), 'Џ', 'dz'),
+        'čČćĆžŽšŠаАбБвВгГдДеЕжЖзЗиИјЈкКлЛмМнНоОпПрРсСтТћЋуУфФхХцЦчЧшШáÁàÀâÂäÄãÃéÉèÈêÊëËíÍìÌîÎïÏóÓòÒôÔöÖõÕúÚùÙûÛüÜñÑçÇýÝ',
+        'cccczzssaabbvvggddeezzzziijjkkllmmnnoopprrssttccuuffhhccccssaaaaaaaaaaeeeeeeeeiiiiiiiioooooooooouuuuuuuunnccyy')), '[^a-z0-9]+', '-', 'g')), 200), '-')
+$$;
+
+-- Spellings that only differ in case, spacing or diacritics ("Stambeni", "stambeni ", "STAMBENI") share a slug
+CREATE TEMPORARY TABLE category_values AS
+SELECT trim(category) AS value, pg_temp.category_slug(category) AS slug, COUNT(*) AS uses
+FROM projects
+WHERE category IS NOT NULL AND pg_temp.category_slug(category) <> ''
+GROUP BY trim(category), pg_temp.category_slug(category);
+
+-- English names of the usual categories are folded into the Serbian one when both are in use
+-- Keep in sync with legacyCategoryAliases in internal/http/handlers/categories.go, which resolves
+-- the English names still sent by older clients
+CREATE TEMPORARY TABLE category_aliases (alias TEXT PRIMARY KEY, canonical TEXT NOT NULL);
+INSERT INTO category_aliases (alias, canonical) VALUES
+    ('residential', 'stambeni'),
+    ('commercial', 'poslovni'),
+    ('business', 'poslovni'),
+    ('industrial', 'industrijski'),
+    ('infrastructure', 'infrastruktura'),
+    ('renovation', 'renoviranje'),
+    ('reconstruction', 'rekonstrukcija');
+
+UPDATE category_values v
+SET slug = a.canonical
+FROM category_aliases a
+WHERE v.slug = a.alias
+  AND EXISTS (SELECT 1 FROM category_values c WHERE c.slug = a.canonical);
+
+-- One category per slug, named after its most used spelling (preferring the canonical language)
+INSERT INTO categories (name, slug)
+SELECT DISTINCT ON (slug) value, slug
+FROM category_values
+ORDER BY slug, pg_temp.category_slug(value) = slug DESC, uses DESC, value;
+
+UPDATE categories c
+SET "order" = o.n
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY name, id) - 1 AS n FROM categories) o
+WHERE o.id = c.id;
+
+UPDATE projects p
+SET category_id = c.id
+FROM category_values v
+JOIN categories c ON c.slug = v.slug
+WHERE v.value = trim(p.category);
+
+ALTER TABLE projects DROP COLUMN category;
+
+DROP TABLE category_aliases;
+DROP TABLE category_values;
+DROP FUNCTION pg_temp.category_slug(TEXT);
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS categories_search ON categories;
+DROP TRIGGER IF EXISTS project_images_search ON project_images;
+DROP TRIGGER IF EXISTS projects_search ON projects;
+DROP FUNCTION IF EXISTS categories_search_trigger();
+DROP FUNCTION IF EXISTS project_images_search_trigger();
+DROP FUNCTION IF EXISTS projects_search_trigger();
+DROP FUNCTION IF EXISTS refresh_project_search(BIGINT);
+DROP TABLE IF EXISTS project_search;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS unaccent_simple;
+DROP EXTENSION IF EXISTS unaccent;
@@ -0,0 +1,72 @@
+-- Full-text search over projects (name, client, category, location, services, description
+-- and image captions). The search document lives in its own table, kept up to date by
+-- triggers, so that SELECT * on projects does not carry the tsvector around.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- The "simple" configuration (no stemming, content is mostly Serbian) with diacritics removed,
+-- so "cacak" finds "Čačak" and ts_headline still highlights the original spelling
+CREATE TEXT SEARCH CONFIGURATION unaccent_simple (COPY = pg_catalog.simple);
+ALTER TEXT SEARCH CONFIGURATION unaccent_simple
+    ALTER MAPPING FOR hword, hword_part, word WITH unaccent, simple;
+
+CREATE TABLE project_search (
+    project_id BIGINT PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    document TSVECTOR NOT NULL
+);
+
+CREATE INDEX idx_project_search_document ON project_search USING GIN (document);
+
+-- Ranked by where the match is: name (A), client and category (B), location, services and description (C), captions (D)
+CREATE FUNCTION refresh_project_search(target_id BIGINT) RETURNS void LANGUAGE sql AS $$
+    INSERT INTO project_search (project_id, document)
+    SELECT p.id,
+        setweight(to_tsvector('unaccent_simple', p.name), 'A')
+        || setweight(to_tsvector('unaccent_simple', concat_ws(' ', p.client, c.name)), 'B')
+        || setweight(to_tsvector('unaccent_simple', concat_ws(' ', p.location, array_to_string(p.services, ' '), p.description)), 'C')
+        || setweight(to_tsvector('unaccent_simple', coalesce((SELECT string_agg(i.caption, ' ') FROM project_images i WHERE i.project_id = p.id), '')), 'D')
+    FROM projects p
+    LEFT JOIN categories c ON c.id = p.category_id
+    WHERE p.id = target_id
+    ON CONFLICT (project_id) DO UPDATE SET document = EXCLUDED.document
+$$;
+
+CREATE FUNCTION projects_search_trigger() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM refresh_project_search(NEW.id);
+    RETURN NULL;
+END
+$$;
+
+-- Also fires when a deleted category sets category_id to NULL
+CREATE TRIGGER projects_search
+    AFTER INSERT OR UPDATE OF name, client, category_id, location, services, description ON projects
+    FOR EACH ROW EXECUTE FUNCTION projects_search_trigger();
+
+CREATE FUNCTION project_images_search_trigger() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_project_search(OLD.project_id);
+    END IF;
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.project_id <> OLD.project_id) THEN
+        PERFORM refresh_project_search(NEW.project_id);
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER project_images_search
+    AFTER INSERT OR UPDATE OF caption, project_id OR DELETE ON project_images
+    FOR EACH ROW EXECUTE FUNCTION project_images_search_trigger();
+
+CREATE FUNCTION categories_search_trigger() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM refresh_project_search(id) FROM projects WHERE category_id = NEW.id;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER categories_search
+    AFTER UPDATE OF name ON categories
+    FOR EACH ROW EXECUTE FUNCTION categories_search_trigger();
+
+SELECT refresh_project_search(id) FROM projects;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_project_image_variants_url;
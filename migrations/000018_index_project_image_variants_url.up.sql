@@ -0,0 +1,2 @@
+-- Image requests look up stored format alternates (e.g. <sha1>.webp) by URL
+CREATE INDEX idx_project_image_variants_url ON project_image_variants(url);
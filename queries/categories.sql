@@ -0,0 +1,57 @@
+-- name: ListCategories :many
+SELECT * FROM categories ORDER BY "order" ASC, name ASC;
+
+-- name: ListCategoriesWithProjectCounts :many
+-- When $1 is true, only published projects inside their publishing window are counted
+SELECT c.id, c.name, c.slug, c."order", c.created_at, c.updated_at, COUNT(p.id) AS project_count
+FROM categories c
+LEFT JOIN projects p ON p.category_id = c.id
+  AND (NOT $1::boolean OR (p.status = 'published'
+    AND (p.publish_at IS NULL OR p.publish_at <= NOW())
+    AND (p.unpublish_at IS NULL OR p.unpublish_at > NOW())))
+GROUP BY c.id
+ORDER BY c."order" ASC, c.name ASC;
+
+-- name: ListCategoriesByIDs :many
+SELECT * FROM categories WHERE id = ANY($1::bigint[]);
+
+-- name: GetCategoryByID :one
+SELECT * FROM categories WHERE id = $1;
+
+-- name: GetCategoryBySlug :one
+SELECT * FROM categories WHERE slug = $1;
+
+-- name: CategorySlugTaken :one
+SELECT EXISTS (SELECT 1 FROM categories WHERE slug = $1 AND id <> $2)::boolean AS taken;
+
+-- name: NextCategoryOrder :one
+SELECT COALESCE(MAX("order") + 1, 0)::int AS next_order FROM categories;
+
+-- name: CreateCategory :one
+INSERT INTO categories (name, slug, "order", created_at, updated_at)
+VALUES ($1, $2, $3, NOW(), NOW())
+RETURNING *;
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET name = $2,
+    slug = $3,
+    "order" = $4,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListCategoryIDsByOrder :many
+-- All category IDs in display order. Locks the rows, so that concurrent reorders apply one after the other
+SELECT id FROM categories ORDER BY "order" ASC, name ASC FOR UPDATE;
+
+-- name: SetCategoryOrder :exec
+-- Numbers the categories listed in $1 0, 1, 2... in that order; rows already in place are left alone
+UPDATE categories c
+SET "order" = o.position - 1,
+    updated_at = NOW()
+FROM unnest($1::bigint[]) WITH ORDINALITY AS o(id, position)
+WHERE c.id = o.id AND c."order" <> o.position - 1;
+
+-- name: DeleteCategory :exec
+DELETE FROM categories WHERE id = $1;
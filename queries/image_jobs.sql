@@ -0,0 +1,41 @@
+-- name: EnqueueImageJob :one
+INSERT INTO image_jobs (project_image_id, status, run_at, created_at, updated_at)
+VALUES ($1, 'pending', NOW(), NOW(), NOW())
+RETURNING *;
+
+-- name: ClaimImageJob :one
+-- Picks the next due job (or one whose worker died while running it) and locks it
+UPDATE image_jobs
+SET status = 'running',
+    attempts = attempts + 1,
+    locked_at = NOW(),
+    updated_at = NOW()
+WHERE id = (
+    SELECT j.id FROM image_jobs j
+    WHERE (j.status = 'pending' AND j.run_at <= NOW())
+       OR (j.status = 'running' AND j.locked_at < NOW() - $1::interval)
+    ORDER BY j.run_at ASC, j.id ASC
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: DeleteImageJob :exec
+DELETE FROM image_jobs WHERE id = $1;
+
+-- name: RetryImageJob :exec
+UPDATE image_jobs
+SET status = 'pending',
+    last_error = $2,
+    run_at = NOW() + $3::interval,
+    locked_at = NULL,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: FailImageJob :exec
+UPDATE image_jobs
+SET status = 'failed',
+    last_error = $2,
+    locked_at = NULL,
+    updated_at = NOW()
+WHERE id = $1;
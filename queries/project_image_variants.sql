@@ -0,0 +1,22 @@
+-- name: ListProjectImageVariantsByImageID :many
+SELECT * FROM project_image_variants WHERE project_image_id = $1 ORDER BY width ASC, format ASC;
+
+-- name: ListProjectImageVariantsByProjectID :many
+SELECT v.* FROM project_image_variants v
+JOIN project_images pi ON pi.id = v.project_image_id
+WHERE pi.project_id = $1
+ORDER BY v.project_image_id ASC, v.width ASC, v.format ASC;
+
+-- name: ListProjectImageVariantsByProjectIDs :many
+SELECT v.* FROM project_image_variants v
+JOIN project_images pi ON pi.id = v.project_image_id
+WHERE pi.project_id = ANY($1::bigint[])
+ORDER BY v.project_image_id ASC, v.width ASC, v.format ASC;
+
+-- name: CreateProjectImageVariant :one
+INSERT INTO project_image_variants (project_image_id, width, height, url, format, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+RETURNING *;
+
+-- name: ProjectImageVariantExists :one
+SELECT EXISTS (SELECT 1 FROM project_image_variants WHERE url = $1 AND format = $2)::boolean AS exists;
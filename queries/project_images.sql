@@ -1,12 +1,24 @@
 -- name: ListProjectImagesByProjectID :many
 SELECT * FROM project_images WHERE project_id = $1 ORDER BY "order" ASC;
 
+-- name: ListProjectImagesByProjectIDs :many
+SELECT * FROM project_images WHERE project_id = ANY($1::bigint[]) ORDER BY project_id ASC, "order" ASC;
+
 -- name: GetProjectImageByID :one
 SELECT * FROM project_images WHERE id = $1;
 
+-- name: GetPublishedProjectImageBlurHash :one
+-- Blurhash of an image whose project is publicly visible
+SELECT pi.blur_hash FROM project_images pi
+JOIN projects p ON p.id = pi.project_id
+WHERE pi.id = $1
+  AND p.status = 'published'
+  AND (p.publish_at IS NULL OR p.publish_at <= NOW())
+  AND (p.unpublish_at IS NULL OR p.unpublish_at > NOW());
+
 -- name: CreateProjectImage :one
-INSERT INTO project_images (name, url, project_id, "order", blur_hash, highlighted, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+INSERT INTO project_images (name, url, project_id, "order", blur_hash, highlighted, captured_at, gps_latitude, gps_longitude, width, height, size_bytes, mime_type, dominant_color, processing_status, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), NOW())
 RETURNING *;
 
 -- name: UpdateProjectImage :exec
@@ -33,3 +45,41 @@ SELECT * FROM project_images WHERE project_id = $1;
 
 -- name: ListProjectImageIDsByProjectID :many
 SELECT id FROM project_images WHERE project_id = $1;
+
+-- name: ListAllProjectImages :many
+SELECT * FROM project_images ORDER BY id ASC;
+
+-- name: ListReferencedStorageURLs :many
+SELECT url FROM project_images
+UNION
+SELECT url FROM project_image_variants;
+
+-- name: ListProjectImagesMissingInfo :many
+SELECT * FROM project_images
+WHERE (width IS NULL OR height IS NULL OR size_bytes IS NULL OR mime_type IS NULL OR dominant_color IS NULL OR blur_hash IS NULL)
+  AND processing_status <> 'processing'
+ORDER BY id ASC;
+
+-- name: UpdateProjectImageInfo :exec
+UPDATE project_images
+SET width = $2,
+    height = $3,
+    size_bytes = $4,
+    mime_type = $5,
+    dominant_color = $6,
+    blur_hash = COALESCE(blur_hash, $7)
+WHERE id = $1;
+
+-- name: UpdateProjectImageProcessingStatus :exec
+UPDATE project_images
+SET processing_status = $2,
+    processing_error = $3
+WHERE id = $1;
+
+-- name: UpdateProjectImageDetails :exec
+UPDATE project_images
+SET name = $2,
+    alt_text = $3,
+    caption = $4,
+    updated_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,18 @@
+-- name: GetRedirectedProjectSlug :one
+-- Current slug of the publicly visible project an old slug belongs to
+SELECT p.slug FROM project_slug_redirects r
+JOIN projects p ON p.id = r.project_id
+WHERE r.slug = $1
+  AND p.status = 'published'
+  AND (p.publish_at IS NULL OR p.publish_at <= NOW())
+  AND (p.unpublish_at IS NULL OR p.unpublish_at > NOW());
+
+-- name: UpsertProjectSlugRedirect :exec
+INSERT INTO project_slug_redirects (slug, project_id, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (slug) DO UPDATE
+SET project_id = EXCLUDED.project_id,
+    created_at = NOW();
+
+-- name: DeleteProjectSlugRedirect :exec
+DELETE FROM project_slug_redirects WHERE slug = $1;
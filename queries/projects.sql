@@ -2,8 +2,14 @@
 SELECT * FROM projects ORDER BY "order" ASC, created_at DESC LIMIT $1 OFFSET $2;
 
 -- name: ListProjectsWithSearch :many
-SELECT * FROM projects 
-WHERE ($1::text IS NULL OR $1::text = '' OR name ILIKE '%' || $1 || '%')
+-- $1 is a tsquery (see searchQuery) matched against project_search: name, client, category,
+-- location, services, description and image captions. Without a sort, matches are ranked by relevance
+SELECT * FROM projects
+WHERE ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($6::text = '' OR status = $6::text)
+  AND ($7::text = '' OR location ILIKE '%' || $7::text || '%')
+  AND ($8::int = 0 OR completion_year = $8::int)
+  AND ($9::text = '' OR $9::text = ANY(services))
 ORDER BY 
   CASE WHEN $2::text = 'order' AND $3::text = 'asc' THEN "order" ELSE NULL END ASC,
   CASE WHEN $2::text = 'order' AND $3::text = 'desc' THEN "order" ELSE NULL END DESC,
@@ -11,44 +17,188 @@ ORDER BY
   CASE WHEN $2::text = 'name' AND $3::text = 'desc' THEN name ELSE NULL END DESC,
   CASE WHEN $2::text = 'created_at' AND $3::text = 'asc' THEN created_at ELSE NULL END ASC,
   CASE WHEN $2::text = 'created_at' AND $3::text = 'desc' THEN created_at ELSE NULL END DESC,
+  CASE WHEN $1::text <> '' AND $2::text NOT IN ('order', 'name', 'created_at') THEN (SELECT ts_rank(document, to_tsquery('unaccent_simple', $1::text)) FROM project_search WHERE project_id = projects.id) ELSE NULL END DESC NULLS LAST,
   CASE WHEN $2::text IS NULL OR $2::text = '' OR $2::text NOT IN ('order', 'name', 'created_at') THEN "order" ELSE NULL END ASC,
   created_at DESC
 LIMIT $4 OFFSET $5;
 
+-- name: ListProjectsKeyset :many
+-- Keyset pagination of ListProjectsWithSearch (same filters $1-$5), ordered by $6 (order, name
+-- or created_at) and id, descending when $7 is true. With a cursor ($11 > 0), only the rows after
+-- its sort value ($8 order, $9 name, $10 created_at) and id are returned.
+SELECT * FROM projects
+WHERE ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($2::text = '' OR status = $2::text)
+  AND ($3::text = '' OR location ILIKE '%' || $3::text || '%')
+  AND ($4::int = 0 OR completion_year = $4::int)
+  AND ($5::text = '' OR $5::text = ANY(services))
+  AND ($11::bigint = 0 OR CASE $6::text
+    WHEN 'name' THEN CASE WHEN $7::boolean THEN (name, id) < ($9::text, $11::bigint) ELSE (name, id) > ($9::text, $11::bigint) END
+    WHEN 'created_at' THEN CASE WHEN $7::boolean THEN (created_at, id) < ($10::timestamp, $11::bigint) ELSE (created_at, id) > ($10::timestamp, $11::bigint) END
+    ELSE CASE WHEN $7::boolean THEN ("order", id) < ($8::int, $11::bigint) ELSE ("order", id) > ($8::int, $11::bigint) END
+  END)
+ORDER BY
+  CASE WHEN $6::text = 'name' AND NOT $7::boolean THEN name END ASC,
+  CASE WHEN $6::text = 'name' AND $7::boolean THEN name END DESC,
+  CASE WHEN $6::text = 'created_at' AND NOT $7::boolean THEN created_at END ASC,
+  CASE WHEN $6::text = 'created_at' AND $7::boolean THEN created_at END DESC,
+  CASE WHEN $6::text NOT IN ('name', 'created_at') AND NOT $7::boolean THEN "order" END ASC,
+  CASE WHEN $6::text NOT IN ('name', 'created_at') AND $7::boolean THEN "order" END DESC,
+  CASE WHEN NOT $7::boolean THEN id END ASC,
+  CASE WHEN $7::boolean THEN id END DESC
+LIMIT $12;
+
 -- name: CountProjects :one
 SELECT COUNT(*) FROM projects;
 
 -- name: CountProjectsWithSearch :one
-SELECT COUNT(*) FROM projects WHERE ($1::text IS NULL OR $1::text = '' OR name ILIKE '%' || $1 || '%');
+SELECT COUNT(*) FROM projects
+WHERE ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($2::text = '' OR status = $2::text)
+  AND ($3::text = '' OR location ILIKE '%' || $3::text || '%')
+  AND ($4::int = 0 OR completion_year = $4::int)
+  AND ($5::text = '' OR $5::text = ANY(services));
+
+-- name: CountPublishedProjects :one
+-- Same filters as ListPublicProjects
+SELECT COUNT(*) FROM projects
+WHERE status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+  AND ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($2::text = '' OR EXISTS (SELECT 1 FROM categories WHERE categories.id = projects.category_id AND categories.slug = $2::text))
+  AND ($3::text = '' OR client ILIKE '%' || $3::text || '%')
+  AND ($4::int = 0 OR completion_year = $4::int)
+  AND ($5::text = '' OR highlighted = ($5::text = 'true'));
 
 -- name: GetProjectByID :one
 SELECT * FROM projects WHERE id = $1;
 
+-- name: GetPublishedProjectByID :one
+SELECT * FROM projects
+WHERE id = $1
+  AND status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW());
+
+-- name: GetPublishedProjectBySlug :one
+SELECT * FROM projects
+WHERE slug = $1
+  AND status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW());
+
+-- name: ProjectSlugTaken :one
+-- Slugs of other projects, current or redirected, cannot be reused
+SELECT (
+  EXISTS (SELECT 1 FROM projects WHERE projects.slug = $1 AND projects.id <> $2)
+  OR EXISTS (SELECT 1 FROM project_slug_redirects WHERE project_slug_redirects.slug = $1 AND project_slug_redirects.project_id <> $2)
+)::boolean AS taken;
+
 -- name: ListPublicProjects :many
-SELECT * FROM projects ORDER BY "order" ASC, created_at DESC;
+-- Filters: $1 search (tsquery, as in ListProjectsWithSearch), $2 category slug, $3 client,
+-- $4 completion year, $5 highlighted ('true'/'false'); empty/0 means no filter.
+-- A limit of 0 returns all matching projects.
+SELECT * FROM projects
+WHERE status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+  AND ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($2::text = '' OR EXISTS (SELECT 1 FROM categories WHERE categories.id = projects.category_id AND categories.slug = $2::text))
+  AND ($3::text = '' OR client ILIKE '%' || $3::text || '%')
+  AND ($4::int = 0 OR completion_year = $4::int)
+  AND ($5::text = '' OR highlighted = ($5::text = 'true'))
+ORDER BY
+  CASE WHEN $6::text = 'order' AND $7::text = 'asc' THEN "order" ELSE NULL END ASC,
+  CASE WHEN $6::text = 'order' AND $7::text = 'desc' THEN "order" ELSE NULL END DESC,
+  CASE WHEN $6::text = 'name' AND $7::text = 'asc' THEN name ELSE NULL END ASC,
+  CASE WHEN $6::text = 'name' AND $7::text = 'desc' THEN name ELSE NULL END DESC,
+  CASE WHEN $6::text = 'created_at' AND $7::text = 'asc' THEN created_at ELSE NULL END ASC,
+  CASE WHEN $6::text = 'created_at' AND $7::text = 'desc' THEN created_at ELSE NULL END DESC,
+  CASE WHEN $6::text = 'completion_year' AND $7::text = 'asc' THEN completion_year ELSE NULL END ASC NULLS LAST,
+  CASE WHEN $6::text = 'completion_year' AND $7::text = 'desc' THEN completion_year ELSE NULL END DESC NULLS LAST,
+  CASE WHEN $1::text <> '' AND $6::text NOT IN ('order', 'name', 'created_at', 'completion_year') THEN (SELECT ts_rank(document, to_tsquery('unaccent_simple', $1::text)) FROM project_search WHERE project_id = projects.id) ELSE NULL END DESC NULLS LAST,
+  CASE WHEN $6::text NOT IN ('order', 'name', 'created_at', 'completion_year') THEN "order" ELSE NULL END ASC,
+  created_at DESC
+LIMIT NULLIF($8::int, 0) OFFSET $9::int;
 
--- name: ListHighlightedProjects :many
-SELECT * FROM projects WHERE highlighted = true ORDER BY "order" ASC, created_at DESC;
+-- name: ListPublicProjectsKeyset :many
+-- Keyset pagination of ListPublicProjects (same filters $1-$5), ordered by $6 (order, name,
+-- created_at or completion_year, missing years as 0) and id, descending when $7 is true.
+-- With a cursor ($11 > 0), only the rows after its sort value ($8 order or completion year,
+-- $9 name, $10 created_at) and id are returned.
+SELECT * FROM projects
+WHERE status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+  AND ($1::text = '' OR id IN (SELECT project_id FROM project_search WHERE document @@ to_tsquery('unaccent_simple', $1::text)))
+  AND ($2::text = '' OR EXISTS (SELECT 1 FROM categories WHERE categories.id = projects.category_id AND categories.slug = $2::text))
+  AND ($3::text = '' OR client ILIKE '%' || $3::text || '%')
+  AND ($4::int = 0 OR completion_year = $4::int)
+  AND ($5::text = '' OR highlighted = ($5::text = 'true'))
+  AND ($11::bigint = 0 OR CASE $6::text
+    WHEN 'name' THEN CASE WHEN $7::boolean THEN (name, id) < ($9::text, $11::bigint) ELSE (name, id) > ($9::text, $11::bigint) END
+    WHEN 'created_at' THEN CASE WHEN $7::boolean THEN (created_at, id) < ($10::timestamp, $11::bigint) ELSE (created_at, id) > ($10::timestamp, $11::bigint) END
+    WHEN 'completion_year' THEN CASE WHEN $7::boolean THEN (COALESCE(completion_year, 0), id) < ($8::int, $11::bigint) ELSE (COALESCE(completion_year, 0), id) > ($8::int, $11::bigint) END
+    ELSE CASE WHEN $7::boolean THEN ("order", id) < ($8::int, $11::bigint) ELSE ("order", id) > ($8::int, $11::bigint) END
+  END)
+ORDER BY
+  CASE WHEN $6::text = 'name' AND NOT $7::boolean THEN name END ASC,
+  CASE WHEN $6::text = 'name' AND $7::boolean THEN name END DESC,
+  CASE WHEN $6::text = 'created_at' AND NOT $7::boolean THEN created_at END ASC,
+  CASE WHEN $6::text = 'created_at' AND $7::boolean THEN created_at END DESC,
+  CASE WHEN $6::text = 'completion_year' AND NOT $7::boolean THEN COALESCE(completion_year, 0) END ASC,
+  CASE WHEN $6::text = 'completion_year' AND $7::boolean THEN COALESCE(completion_year, 0) END DESC,
+  CASE WHEN $6::text NOT IN ('name', 'created_at', 'completion_year') AND NOT $7::boolean THEN "order" END ASC,
+  CASE WHEN $6::text NOT IN ('name', 'created_at', 'completion_year') AND $7::boolean THEN "order" END DESC,
+  CASE WHEN NOT $7::boolean THEN id END ASC,
+  CASE WHEN $7::boolean THEN id END DESC
+LIMIT $12;
 
--- name: ListPublicProjectsPaginated :many
-SELECT * FROM projects ORDER BY "order" ASC, created_at DESC LIMIT $1 OFFSET $2;
+-- name: ListHighlightedProjects :many
+SELECT * FROM projects
+WHERE highlighted = true
+  AND status = 'published'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+ORDER BY "order" ASC, created_at DESC;
 
 -- name: CreateProject :one
-INSERT INTO projects (status, name, category, client, "order", highlighted, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+INSERT INTO projects (status, name, category_id, client, "order", highlighted, slug, publish_at, unpublish_at, description, location, completion_year, area_m2, duration_months, services, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), NOW())
 RETURNING *;
 
 -- name: UpdateProject :exec
 UPDATE projects
 SET status = $2,
     name = $3,
-    category = $4,
+    category_id = $4,
     client = $5,
     "order" = $6,
     highlighted = $7,
+    slug = $8,
+    publish_at = $9,
+    unpublish_at = $10,
+    description = $11,
+    location = $12,
+    completion_year = $13,
+    area_m2 = $14,
+    duration_months = $15,
+    services = $16,
     updated_at = NOW()
 WHERE id = $1;
 
+-- name: UpdateProjectStatus :one
+-- Moves a project to a new status, only when its current status is one of $3.
+-- A schedule that already fired is cleared so the scheduler does not undo the change.
+UPDATE projects
+SET status = $2,
+    publish_at = CASE WHEN $2 <> 'published' AND publish_at <= NOW() THEN NULL ELSE publish_at END,
+    unpublish_at = CASE WHEN $2 = 'published' AND unpublish_at <= NOW() THEN NULL ELSE unpublish_at END,
+    updated_at = NOW()
+WHERE id = $1 AND status = ANY($3::text[])
+RETURNING *;
+
 -- name: ToggleProjectHighlight :exec
 UPDATE projects
 SET highlighted = NOT highlighted,
@@ -57,3 +207,53 @@ WHERE id = $1;
 
 -- name: DeleteProject :exec
 DELETE FROM projects WHERE id = $1;
+
+-- name: PublishScheduledProjects :many
+-- Drafts whose publish_at has passed (and whose unpublish_at has not)
+UPDATE projects
+SET status = 'published',
+    updated_at = NOW()
+WHERE status = 'draft'
+  AND publish_at <= NOW()
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+RETURNING id, publish_at;
+
+-- name: UnpublishScheduledProjects :many
+-- Published projects whose unpublish_at has passed
+UPDATE projects
+SET status = 'archived',
+    updated_at = NOW()
+WHERE status = 'published'
+  AND unpublish_at <= NOW()
+RETURNING id, unpublish_at;
+
+-- name: MoveProjectsToCategory :exec
+UPDATE projects
+SET category_id = $2,
+    updated_at = NOW()
+WHERE category_id = $1;
+
+-- name: ListProjectSearchSnippets :many
+-- Fragments of the given projects' text matching the tsquery $2, with the matches in <mark> tags
+SELECT p.id AS project_id,
+    ts_headline('unaccent_simple',
+        concat_ws(' · ', p.name, p.client, c.name, p.location, array_to_string(p.services, ', '), p.description,
+            (SELECT string_agg(i.caption, ' · ' ORDER BY i."order") FROM project_images i WHERE i.project_id = p.id)),
+        to_tsquery('unaccent_simple', $2::text),
+        'StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=25, MaxFragments=2, FragmentDelimiter=" … "')::text AS snippet
+FROM projects p
+LEFT JOIN categories c ON c.id = p.category_id
+WHERE p.id = ANY($1::bigint[]);
+
+-- name: ListProjectIDsByOrder :many
+-- All project IDs in display order (as in the admin list). Locks the rows,
+-- so that concurrent reorders apply one after the other
+SELECT id FROM projects ORDER BY "order" ASC, created_at DESC, id ASC FOR UPDATE;
+
+-- name: SetProjectOrder :exec
+-- Numbers the projects listed in $1 0, 1, 2... in that order; rows already in place are left alone
+UPDATE projects p
+SET "order" = o.position - 1,
+    updated_at = NOW()
+FROM unnest($1::bigint[]) WITH ORDINALITY AS o(id, position)
+WHERE p.id = o.id AND p."order" <> o.position - 1;
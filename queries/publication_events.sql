@@ -0,0 +1,31 @@
+-- name: CreatePublicationEvent :exec
+INSERT INTO publication_events (entity_type, entity_id, action, from_status, to_status, scheduled_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, NOW());
+
+-- name: ListPublicationEvents :many
+SELECT * FROM publication_events
+WHERE ($1::text = '' OR entity_type = $1::text)
+  AND ($2::bigint = 0 OR entity_id = $2::bigint)
+ORDER BY created_at DESC, id DESC
+LIMIT $3 OFFSET $4;
+
+-- name: ListPublicationEventsKeyset :many
+-- Keyset pagination of ListPublicationEvents (same filters $1, $2): the rows after the cursor
+-- ($4, $5), or before it when $3 is true (then in reverse order). $5 = 0 starts at the top.
+SELECT * FROM publication_events
+WHERE ($1::text = '' OR entity_type = $1::text)
+  AND ($2::bigint = 0 OR entity_id = $2::bigint)
+  AND ($5::bigint = 0
+    OR ($3::boolean AND (created_at, id) > ($4::timestamp, $5::bigint))
+    OR (NOT $3::boolean AND (created_at, id) < ($4::timestamp, $5::bigint)))
+ORDER BY
+  CASE WHEN $3::boolean THEN created_at END ASC,
+  CASE WHEN $3::boolean THEN id END ASC,
+  CASE WHEN NOT $3::boolean THEN created_at END DESC,
+  CASE WHEN NOT $3::boolean THEN id END DESC
+LIMIT $6;
+
+-- name: CountPublicationEvents :one
+SELECT COUNT(*) FROM publication_events
+WHERE ($1::text = '' OR entity_type = $1::text)
+  AND ($2::bigint = 0 OR entity_id = $2::bigint);
@@ -1,6 +1,20 @@
 -- name: ListStaticTexts :many
 SELECT * FROM static_texts ORDER BY created_at DESC LIMIT $1 OFFSET $2;
 
+-- name: ListStaticTextsKeyset :many
+-- Keyset pagination in created_at DESC, id DESC order: the rows after the cursor ($2, $3),
+-- or before it when $1 is true (then in reverse order). $3 = 0 starts at the top.
+SELECT * FROM static_texts
+WHERE $3::bigint = 0
+   OR ($1::boolean AND (created_at, id) > ($2::timestamp, $3::bigint))
+   OR (NOT $1::boolean AND (created_at, id) < ($2::timestamp, $3::bigint))
+ORDER BY
+  CASE WHEN $1::boolean THEN created_at END ASC,
+  CASE WHEN $1::boolean THEN id END ASC,
+  CASE WHEN NOT $1::boolean THEN created_at END DESC,
+  CASE WHEN NOT $1::boolean THEN id END DESC
+LIMIT $4;
+
 -- name: CountStaticTexts :one
 SELECT COUNT(*) FROM static_texts;
 
@@ -15,3 +29,17 @@ UPDATE static_texts
 SET content = $2,
     updated_at = NOW()
 WHERE id = $1;
+
+-- name: SearchStaticTexts :many
+-- Admin search (tsquery $1, see searchQuery) in key, label and content, best matches first.
+-- snippet has the matches in <mark> tags; total is the number of matches
+SELECT st.id, st.label AS title,
+    ts_headline('unaccent_simple', concat_ws(' · ', st.label, st.content), query,
+        'StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=25')::text AS snippet,
+    COUNT(*) OVER () AS total
+FROM static_texts st
+CROSS JOIN to_tsquery('unaccent_simple', $1::text) AS query
+CROSS JOIN LATERAL to_tsvector('unaccent_simple', concat_ws(' ', translate(st.key, '_.-', '   '), st.label, st.content)) AS document
+WHERE document @@ query
+ORDER BY ts_rank(document, query) DESC, st.key, st.id
+LIMIT $2;
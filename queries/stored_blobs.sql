@@ -0,0 +1,24 @@
+-- name: AcquireStoredBlob :one
+INSERT INTO stored_blobs (url, ref_count, created_at, updated_at)
+VALUES ($1, 1, NOW(), NOW())
+ON CONFLICT (url) DO UPDATE
+SET ref_count = stored_blobs.ref_count + 1,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ReleaseStoredBlob :one
+UPDATE stored_blobs
+SET ref_count = GREATEST(ref_count - 1, 0),
+    updated_at = NOW()
+WHERE url = $1
+RETURNING ref_count;
+
+-- name: DeleteUnreferencedStoredBlob :execrows
+DELETE FROM stored_blobs WHERE url = $1 AND ref_count = 0;
+
+-- name: StoredFileInUse :one
+-- Checked after a release commits, in case an identical upload re-acquired the file
+SELECT (
+  EXISTS (SELECT 1 FROM stored_blobs WHERE url = $1 AND ref_count > 0)
+  OR EXISTS (SELECT 1 FROM project_images WHERE url = $1)
+)::boolean AS in_use;
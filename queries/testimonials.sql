@@ -1,28 +1,82 @@
 -- name: ListTestimonials :many
 SELECT * FROM testimonials ORDER BY created_at DESC LIMIT $1 OFFSET $2;
 
+-- name: ListTestimonialsKeyset :many
+-- Keyset pagination in created_at DESC, id DESC order: the rows after the cursor ($2, $3),
+-- or before it when $1 is true (then in reverse order). $3 = 0 starts at the top.
+SELECT * FROM testimonials
+WHERE $3::bigint = 0
+   OR ($1::boolean AND (created_at, id) > ($2::timestamp, $3::bigint))
+   OR (NOT $1::boolean AND (created_at, id) < ($2::timestamp, $3::bigint))
+ORDER BY
+  CASE WHEN $1::boolean THEN created_at END ASC,
+  CASE WHEN $1::boolean THEN id END ASC,
+  CASE WHEN NOT $1::boolean THEN created_at END DESC,
+  CASE WHEN NOT $1::boolean THEN id END DESC
+LIMIT $4;
+
 -- name: CountTestimonials :one
 SELECT COUNT(*) FROM testimonials;
 
 -- name: ListPublicTestimonials :many
-SELECT * FROM testimonials WHERE status = 'ready' ORDER BY created_at DESC;
+SELECT * FROM testimonials
+WHERE status = 'ready'
+  AND (publish_at IS NULL OR publish_at <= NOW())
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+ORDER BY created_at DESC;
 
 -- name: GetTestimonialByID :one
 SELECT * FROM testimonials WHERE id = $1;
 
 -- name: CreateTestimonial :one
-INSERT INTO testimonials (full_name, profession, testimonial, status, created_at, updated_at)
-VALUES ($1, $2, $3, $4, NOW(), NOW())
+INSERT INTO testimonials (full_name, profession, testimonial, status, publish_at, unpublish_at, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
 RETURNING *;
 
 -- name: UpdateTestimonial :exec
+-- A schedule that already fired is cleared so the scheduler does not undo the status change.
 UPDATE testimonials
 SET full_name = $2,
     profession = $3,
     testimonial = $4,
     status = $5,
+    publish_at = CASE WHEN $5 <> 'ready' AND $6::timestamptz <= NOW() THEN NULL ELSE $6::timestamptz END,
+    unpublish_at = CASE WHEN $5 = 'ready' AND $7::timestamptz <= NOW() THEN NULL ELSE $7::timestamptz END,
     updated_at = NOW()
 WHERE id = $1;
 
 -- name: DeleteTestimonial :exec
 DELETE FROM testimonials WHERE id = $1;
+
+-- name: PublishScheduledTestimonials :many
+-- Pending testimonials whose publish_at has passed (and whose unpublish_at has not)
+UPDATE testimonials
+SET status = 'ready',
+    updated_at = NOW()
+WHERE status = 'pending'
+  AND publish_at <= NOW()
+  AND (unpublish_at IS NULL OR unpublish_at > NOW())
+RETURNING id, publish_at;
+
+-- name: UnpublishScheduledTestimonials :many
+-- Ready testimonials whose unpublish_at has passed
+UPDATE testimonials
+SET status = 'pending',
+    updated_at = NOW()
+WHERE status = 'ready'
+  AND unpublish_at <= NOW()
+RETURNING id, unpublish_at;
+
+-- name: SearchTestimonials :many
+-- Admin search (tsquery $1, see searchQuery) in name, profession and text, best matches first.
+-- snippet has the matches in <mark> tags; total is the number of matches
+SELECT t.id, t.full_name AS title,
+    ts_headline('unaccent_simple', concat_ws(' · ', t.full_name, t.profession, t.testimonial), query,
+        'StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=25')::text AS snippet,
+    COUNT(*) OVER () AS total
+FROM testimonials t
+CROSS JOIN to_tsquery('unaccent_simple', $1::text) AS query
+CROSS JOIN LATERAL to_tsvector('unaccent_simple', concat_ws(' ', t.full_name, t.profession, t.testimonial)) AS document
+WHERE document @@ query
+ORDER BY ts_rank(document, query) DESC, t.created_at DESC, t.id DESC
+LIMIT $2;
@@ -0,0 +1,30 @@
+-- name: CreateUpload :one
+INSERT INTO uploads (id, filename, size, received, status, expires_at, created_at, updated_at)
+VALUES ($1, $2, $3, 0, 'uploading', NOW() + INTERVAL '24 hours', NOW(), NOW())
+RETURNING *;
+
+-- name: GetUpload :one
+SELECT * FROM uploads WHERE id = $1 AND expires_at > NOW();
+
+-- name: GetUploadForUpdate :one
+SELECT * FROM uploads WHERE id = $1 AND expires_at > NOW() FOR UPDATE;
+
+-- name: AdvanceUploadReceived :execrows
+UPDATE uploads
+SET received = $3,
+    updated_at = NOW()
+WHERE id = $1 AND received = $2 AND status = 'uploading';
+
+-- name: CompleteUpload :one
+UPDATE uploads
+SET status = 'complete',
+    sha1 = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteUpload :exec
+DELETE FROM uploads WHERE id = $1;
+
+-- name: ListExpiredUploadIDs :many
+SELECT id FROM uploads WHERE expires_at <= NOW();
@@ -10,6 +10,20 @@ SELECT * FROM users WHERE reset_token_hash = $1 AND reset_token_expires_at > NOW
 -- name: ListUsers :many
 SELECT * FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2;
 
+-- name: ListUsersKeyset :many
+-- Keyset pagination in created_at DESC, id DESC order: the rows after the cursor ($2, $3),
+-- or before it when $1 is true (then in reverse order). $3 = 0 starts at the top.
+SELECT * FROM users
+WHERE $3::bigint = 0
+   OR ($1::boolean AND (created_at, id) > ($2::timestamp, $3::bigint))
+   OR (NOT $1::boolean AND (created_at, id) < ($2::timestamp, $3::bigint))
+ORDER BY
+  CASE WHEN $1::boolean THEN created_at END ASC,
+  CASE WHEN $1::boolean THEN id END ASC,
+  CASE WHEN NOT $1::boolean THEN created_at END DESC,
+  CASE WHEN NOT $1::boolean THEN id END DESC
+LIMIT $4;
+
 -- name: CountUsers :one
 SELECT COUNT(*) FROM users;
 
@@ -47,3 +61,17 @@ WHERE id = $1;
 
 -- name: DeleteUser :exec
 DELETE FROM users WHERE id = $1;
+
+-- name: SearchUsers :many
+-- Admin search (tsquery $1, see searchQuery) in name and email, best matches first.
+-- snippet has the matches in <mark> tags; total is the number of matches
+SELECT u.id, u.name AS title,
+    ts_headline('unaccent_simple', concat_ws(' · ', u.name, u.email), query,
+        'StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=25')::text AS snippet,
+    COUNT(*) OVER () AS total
+FROM users u
+CROSS JOIN to_tsquery('unaccent_simple', $1::text) AS query
+CROSS JOIN LATERAL to_tsvector('unaccent_simple', concat_ws(' ', u.name, translate(u.email, '@.', '  '))) AS document
+WHERE document @@ query
+ORDER BY ts_rank(document, query) DESC, u.name, u.id
+LIMIT $2;
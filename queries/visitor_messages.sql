@@ -1,6 +1,20 @@
 -- name: ListVisitorMessages :many
 SELECT * FROM visitor_messages ORDER BY created_at DESC LIMIT $1 OFFSET $2;
 
+-- name: ListVisitorMessagesKeyset :many
+-- Keyset pagination in created_at DESC, id DESC order: the rows after the cursor ($2, $3),
+-- or before it when $1 is true (then in reverse order). $3 = 0 starts at the top.
+SELECT * FROM visitor_messages
+WHERE $3::bigint = 0
+   OR ($1::boolean AND (created_at, id) > ($2::timestamp, $3::bigint))
+   OR (NOT $1::boolean AND (created_at, id) < ($2::timestamp, $3::bigint))
+ORDER BY
+  CASE WHEN $1::boolean THEN created_at END ASC,
+  CASE WHEN $1::boolean THEN id END ASC,
+  CASE WHEN NOT $1::boolean THEN created_at END DESC,
+  CASE WHEN NOT $1::boolean THEN id END DESC
+LIMIT $4;
+
 -- name: CountVisitorMessages :one
 SELECT COUNT(*) FROM visitor_messages;
 
@@ -11,3 +25,17 @@ RETURNING *;
 
 -- name: DeleteVisitorMessage :exec
 DELETE FROM visitor_messages WHERE id = $1;
+
+-- name: SearchVisitorMessages :many
+-- Admin search (tsquery $1, see searchQuery) in email, address and message, best matches first.
+-- snippet has the matches in <mark> tags; total is the number of matches
+SELECT m.id, m.email AS title,
+    ts_headline('unaccent_simple', concat_ws(' · ', m.email, m.address, m.description), query,
+        'StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=25')::text AS snippet,
+    COUNT(*) OVER () AS total
+FROM visitor_messages m
+CROSS JOIN to_tsquery('unaccent_simple', $1::text) AS query
+CROSS JOIN LATERAL to_tsvector('unaccent_simple', concat_ws(' ', translate(m.email, '@.', '  '), m.address, m.description)) AS document
+WHERE document @@ query
+ORDER BY ts_rank(document, query) DESC, m.created_at DESC, m.id DESC
+LIMIT $2;